@@ -3,21 +3,24 @@ package handler
 import (
 	"log"
 	"net/http"
+	"os"
 	"sync"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/config"
 	"github.com/Albaihaqi354/Tickitz-BE/core/middleware"
 	"github.com/Albaihaqi354/Tickitz-BE/core/router"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
 var (
-	app  *gin.Engine
-	db   *pgxpool.Pool
-	rdb  *redis.Client
-	once sync.Once
+	app     *gin.Engine
+	db      *pgxpool.Pool
+	rdb     *redis.Client
+	initErr error
+	once    sync.Once
 )
 
 func initApp() {
@@ -32,21 +35,46 @@ func initApp() {
 		// Initialize Redis
 		rdb = config.InitRedis()
 
+		// Initialize Payment Provider
+		provider, err := pkg.NewPaymentProvider(os.Getenv("PAYMENT_PROVIDER"))
+		if err != nil {
+			log.Println("Vercel: Failed to init payment provider:", err)
+			initErr = err
+			return
+		}
+
+		// Initialize Mailer
+		mailer, err := pkg.NewMailer(os.Getenv("MAIL_DRIVER"))
+		if err != nil {
+			log.Println("Vercel: Failed to init mailer:", err)
+			initErr = err
+			return
+		}
+
 		// Initialize Gin
 		gin.SetMode(gin.ReleaseMode)
 		app = gin.New()
 		app.Use(gin.Recovery())
+		if err := config.InitTrustedProxies(app); err != nil {
+			log.Println("Vercel: Failed to set trusted proxies:", err)
+			initErr = err
+			return
+		}
 		
 		// Add CORS middleware
 		app.Use(middleware.CORSMiddleware)
 
 		// Initialize Routes
-		router.Init(app, db, rdb)
+		router.Init(app, db, rdb, provider, mailer)
 	})
 }
 
 // Handler is the entry point for Vercel Serverless Functions
 func Handler(w http.ResponseWriter, r *http.Request) {
 	initApp()
+	if initErr != nil {
+		http.Error(w, "server is not configured", http.StatusInternalServerError)
+		return
+	}
 	app.ServeHTTP(w, r)
 }
@@ -1,11 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/config"
 	"github.com/Albaihaqi354/Tickitz-BE/core/middleware"
 	"github.com/Albaihaqi354/Tickitz-BE/core/router"
+	"github.com/Albaihaqi354/Tickitz-BE/core/worker"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/lpernett/godotenv"
 )
@@ -36,9 +46,57 @@ func main() {
 	rdb := config.InitRedis()
 	defer rdb.Close()
 
+	provider, err := pkg.NewPaymentProvider(os.Getenv("PAYMENT_PROVIDER"))
+	if err != nil {
+		log.Println("Failed to init payment provider:", err.Error())
+		return
+	}
+
+	mailer, err := pkg.NewMailer(os.Getenv("MAIL_DRIVER"))
+	if err != nil {
+		log.Println("Failed to init mailer:", err.Error())
+		return
+	}
+
 	app := gin.Default()
+	if err := config.InitTrustedProxies(app); err != nil {
+		log.Println("Failed to set trusted proxies:", err.Error())
+		return
+	}
 
 	app.Use(middleware.CORSMiddleware)
-	router.Init(app, db, rdb)
-	app.Run(":5000")
+	router.Init(app, db, rdb, provider, mailer)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	expiryWorker := worker.NewOrderExpiryWorker(router.NewOrderService(db, rdb, provider), pkg.GetEnvDuration("ORDER_EXPIRY_INTERVAL", time.Minute))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		expiryWorker.Run(ctx)
+	}()
+
+	srv := &http.Server{
+		Addr:    ":5000",
+		Handler: app,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Server Error:", err.Error())
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Server Shutdown Error:", err.Error())
+	}
+	wg.Wait()
 }
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InitTrustedProxies decides where c.ClientIP() takes the client address
+// from, which login throttling and sessions rely on. X-Forwarded-For is only
+// believed from the addresses or CIDRs in TRUSTED_PROXIES (comma separated);
+// without any, the address of the connection is used. TRUSTED_PLATFORM names
+// a header set by the hosting platform instead, e.g. X-Real-IP on Vercel.
+func InitTrustedProxies(app *gin.Engine) error {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	app.TrustedPlatform = os.Getenv("TRUSTED_PLATFORM")
+	return app.SetTrustedProxies(proxies)
+}
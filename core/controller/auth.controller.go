@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
 	"github.com/Albaihaqi354/Tickitz-BE/core/service"
 	"github.com/gin-gonic/gin"
 )
@@ -71,7 +74,7 @@ func (a AuthController) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user and return JWT token
+// @Description  Authenticate user and return a JWT access token and a refresh token. Failed attempts are throttled per email and per IP address; while throttled the response is 429 with a Retry-After header
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -79,6 +82,9 @@ func (a AuthController) Register(c *gin.Context) {
 // @Success      200          {object}  dto.Response{data=dto.LoginResponse}
 // @Failure      400          {object}  dto.Response
 // @Failure      401          {object}  dto.Response
+// @Failure      403          {object}  dto.Response
+// @Failure      429          {object}  dto.Response
+// @Header       429          {integer}  Retry-After  "Seconds until the next attempt is allowed"
 // @Router       /auth/login [post]
 func (a AuthController) Login(c *gin.Context) {
 	var loginReq dto.LoginRequest
@@ -92,8 +98,31 @@ func (a AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	loginReq.UserAgent = c.Request.UserAgent()
+	loginReq.IpAddress = c.ClientIP()
+
 	data, err := a.authService.Login(c.Request.Context(), loginReq)
 	if err != nil {
+		var throttledErr *apperr.LoginThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(throttledErr.Seconds()))
+			c.JSON(http.StatusTooManyRequests, dto.Response{
+				Msg:     "Too Many Requests",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
+		if errors.Is(err, apperr.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, dto.Response{
+				Msg:     "Forbidden",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, dto.Response{
 			Msg:     "Unauthorized",
 			Success: false,
@@ -110,15 +139,273 @@ func (a AuthController) Login(c *gin.Context) {
 	})
 }
 
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Mark the email address of an account as verified using the link sent by email
+// @Tags         auth
+// @Produce      json
+// @Param        token  query     string  true  "Verification Token"
+// @Success      200    {object}  dto.Response
+// @Failure      400    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /auth/verify [get]
+func (a AuthController) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "Missing token parameter",
+			Data:    []any{},
+		})
+		return
+	}
+
+	if err := a.authService.VerifyEmail(c.Request.Context(), token); err != nil {
+		if errors.Is(err, apperr.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    []any{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Verify Email Success",
+		Success: true,
+		Data:    []any{},
+	})
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification link to an unverified account. The response is the same whether or not the email has an account. Only one link per email is sent every MAIL_COOLDOWN
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        email  body      dto.ResendVerificationRequest  true  "Account Email"
+// @Success      200    {object}  dto.Response
+// @Failure      400    {object}  dto.Response
+// @Failure      429    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /auth/verify/resend [post]
+func (a AuthController) ResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "invalid request body",
+			Data:    []any{},
+		})
+		return
+	}
+
+	req.IpAddress = c.ClientIP()
+	if err := a.authService.ResendVerification(c.Request.Context(), req); err != nil {
+		var throttledErr *apperr.RequestThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(throttledErr.Seconds()))
+			c.JSON(http.StatusTooManyRequests, dto.Response{
+				Msg:     "Too Many Requests",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    []any{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "If the email has an unverified account, a verification link has been sent",
+		Success: true,
+		Data:    []any{},
+	})
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Send a single-use password reset link to the email. The response is the same whether or not the email has an account. Only one link per email is sent every MAIL_COOLDOWN
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        email  body      dto.ForgotPasswordRequest  true  "Account Email"
+// @Success      200    {object}  dto.Response
+// @Failure      400    {object}  dto.Response
+// @Failure      429    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /auth/forgot-password [post]
+func (a AuthController) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "invalid request body",
+			Data:    []any{},
+		})
+		return
+	}
+
+	req.IpAddress = c.ClientIP()
+	if err := a.authService.ForgotPassword(c.Request.Context(), req); err != nil {
+		var throttledErr *apperr.RequestThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(throttledErr.Seconds()))
+			c.JSON(http.StatusTooManyRequests, dto.Response{
+				Msg:     "Too Many Requests",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    []any{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "If the email has an account, a password reset link has been sent",
+		Success: true,
+		Data:    []any{},
+	})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password using the token from the reset link. The token can only be used once, and every session of the user is logged out
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        reset  body      dto.ResetPasswordRequest  true  "Reset Token and New Password"
+// @Success      200    {object}  dto.Response
+// @Failure      400    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /auth/reset-password [post]
+func (a AuthController) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "invalid request body",
+			Data:    []any{},
+		})
+		return
+	}
+
+	if err := a.authService.ResetPassword(c.Request.Context(), req); err != nil {
+		if errors.Is(err, apperr.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    []any{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Reset Password Success",
+		Success: true,
+		Data:    []any{},
+	})
+}
+
+// Refresh godoc
+// @Summary      Refresh the access token
+// @Description  Exchange a refresh token for a new access token and a new refresh token. A refresh token can only be used once; using it again revokes every refresh token issued from the same login
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token  body      dto.RefreshTokenRequest  true  "Refresh Token"
+// @Success      200    {object}  dto.Response{data=dto.LoginResponse}
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /auth/refresh [post]
+func (a AuthController) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "invalid request body",
+			Data:    []any{},
+		})
+		return
+	}
+
+	req.IpAddress = c.ClientIP()
+
+	data, err := a.authService.Refresh(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, apperr.ErrInvalidRefreshToken) || errors.Is(err, apperr.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, dto.Response{
+				Msg:     "Unauthorized",
+				Success: false,
+				Error:   err.Error(),
+				Data:    []any{},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    []any{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Refresh Token Success",
+		Success: true,
+		Data:    []any{data},
+	})
+}
+
 // Logout godoc
 // @Summary      User logout
-// @Description  Invalidate JWT token by removing it from whitelist
+// @Description  Revoke the session of the JWT token, removing the token from whitelist and revoking its refresh tokens. When a refresh token is sent, it is revoked as well
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200  {object}  dto.Response
-// @Failure      401  {object}  dto.Response
+// @Param        token  body      dto.LogoutRequest  false  "Refresh Token"
+// @Success      200    {object}  dto.Response
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
 // @Router       /auth/logout [delete]
 func (a AuthController) Logout(c *gin.Context) {
 	bearerToken := c.GetHeader("Authorization")
@@ -133,7 +420,19 @@ func (a AuthController) Logout(c *gin.Context) {
 	}
 	token := parts[1]
 
-	err := a.authService.Logout(c.Request.Context(), token)
+	var req dto.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Error:   "invalid request body",
+			})
+			return
+		}
+	}
+
+	err := a.authService.Logout(c.Request.Context(), c.GetInt("user_id"), c.GetInt("session_id"), token, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Response{
 			Msg:     "Internal Server Error",
@@ -0,0 +1,696 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogController struct {
+	catalogService *service.CatalogService
+}
+
+func NewCatalogController(catalogService *service.CatalogService) *CatalogController {
+	return &CatalogController{
+		catalogService: catalogService,
+	}
+}
+
+func catalogError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apperr.ErrInvalidCast):
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrDirectorNotFound), errors.Is(err, apperr.ErrActorNotFound),
+		errors.Is(err, apperr.ErrGenreNotFound), errors.Is(err, apperr.ErrMovieNotFound):
+		c.JSON(http.StatusNotFound, dto.Response{
+			Msg:     "Not Found",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrGenreNameTaken):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Conflict",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	}
+}
+
+// GetDirectors godoc
+// @Summary      List directors
+// @Description  List directors with their movie count, optionally filtered by name (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        search  query     string  false  "Part of the name"
+// @Success      200     {object}  dto.Response{data=[]dto.DirectorResponse}
+// @Failure      401     {object}  dto.Response
+// @Failure      500     {object}  dto.Response
+// @Router       /admin/directors [get]
+func (ctrl CatalogController) GetDirectors(c *gin.Context) {
+	data, err := ctrl.catalogService.GetDirectors(c.Request.Context(), c.Query("search"))
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Directors Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateDirector godoc
+// @Summary      Create a director
+// @Description  Create a director (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        director  body      dto.PersonRequest  true  "Director Body"
+// @Success      201       {object}  dto.Response{data=dto.DirectorResponse}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      500       {object}  dto.Response
+// @Router       /admin/directors [post]
+func (ctrl CatalogController) CreateDirector(c *gin.Context) {
+	var req dto.PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.CreateDirector(c.Request.Context(), req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Director Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateDirector godoc
+// @Summary      Rename a director
+// @Description  Rename a director (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                true  "Director ID"
+// @Param        director  body      dto.PersonRequest  true  "Director Body"
+// @Success      200       {object}  dto.Response{data=dto.DirectorResponse}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      500       {object}  dto.Response
+// @Router       /admin/directors/{id} [put]
+func (ctrl CatalogController) UpdateDirector(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.UpdateDirector(c.Request.Context(), id, req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Director Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteDirector godoc
+// @Summary      Delete a director
+// @Description  Delete a director. Their movies are kept without a director (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Director ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/directors/{id} [delete]
+func (ctrl CatalogController) DeleteDirector(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.catalogService.DeleteDirector(c.Request.Context(), id); err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Director Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetActors godoc
+// @Summary      List actors
+// @Description  List actors with their movie count, optionally filtered by name (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        search  query     string  false  "Part of the name"
+// @Success      200     {object}  dto.Response{data=[]dto.ActorResponse}
+// @Failure      401     {object}  dto.Response
+// @Failure      500     {object}  dto.Response
+// @Router       /admin/actors [get]
+func (ctrl CatalogController) GetActors(c *gin.Context) {
+	data, err := ctrl.catalogService.GetActors(c.Request.Context(), c.Query("search"))
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Actors Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateActor godoc
+// @Summary      Create a actor
+// @Description  Create an actor (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        actor  body      dto.PersonRequest  true  "Actor Body"
+// @Success      201    {object}  dto.Response{data=dto.ActorResponse}
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /admin/actors [post]
+func (ctrl CatalogController) CreateActor(c *gin.Context) {
+	var req dto.PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.CreateActor(c.Request.Context(), req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Actor Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateActor godoc
+// @Summary      Rename a actor
+// @Description  Rename a actor (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int                true  "Actor ID"
+// @Param        actor  body      dto.PersonRequest  true  "Actor Body"
+// @Success      200    {object}  dto.Response{data=dto.ActorResponse}
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
+// @Failure      404    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /admin/actors/{id} [put]
+func (ctrl CatalogController) UpdateActor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.UpdateActor(c.Request.Context(), id, req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Actor Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteActor godoc
+// @Summary      Delete a actor
+// @Description  Delete an actor and remove them from the cast of their movies (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Actor ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/actors/{id} [delete]
+func (ctrl CatalogController) DeleteActor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.catalogService.DeleteActor(c.Request.Context(), id); err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Actor Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetGenres godoc
+// @Summary      List genres
+// @Description  List genres with their movie count (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.GenreResponse}
+// @Failure      401  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/genres [get]
+func (ctrl CatalogController) GetGenres(c *gin.Context) {
+	data, err := ctrl.catalogService.GetGenres(c.Request.Context())
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Genres Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateGenre godoc
+// @Summary      Create a genre
+// @Description  Create a genre. Genre names are unique (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        genre  body      dto.GenreRequest  true  "Genre Body"
+// @Success      201    {object}  dto.Response{data=dto.GenreResponse}
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
+// @Failure      409    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /admin/genres [post]
+func (ctrl CatalogController) CreateGenre(c *gin.Context) {
+	var req dto.GenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.CreateGenre(c.Request.Context(), req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Genre Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateGenre godoc
+// @Summary      Rename a genre
+// @Description  Rename a genre (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int               true  "Genre ID"
+// @Param        genre  body      dto.GenreRequest  true  "Genre Body"
+// @Success      200    {object}  dto.Response{data=dto.GenreResponse}
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
+// @Failure      404    {object}  dto.Response
+// @Failure      409    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /admin/genres/{id} [put]
+func (ctrl CatalogController) UpdateGenre(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.GenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.UpdateGenre(c.Request.Context(), id, req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Genre Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteGenre godoc
+// @Summary      Delete a genre
+// @Description  Delete a genre and remove it from its movies (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Genre ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/genres/{id} [delete]
+func (ctrl CatalogController) DeleteGenre(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.catalogService.DeleteGenre(c.Request.Context(), id); err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Genre Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetMovieCast godoc
+// @Summary      Get a movie's cast
+// @Description  Get the cast of a movie, billed actors first (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Movie ID"
+// @Success      200  {object}  dto.Response{data=[]dto.MovieCastResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/movies/{id}/cast [get]
+func (ctrl CatalogController) GetMovieCast(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.GetMovieCast(c.Request.Context(), id)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Movie Cast Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// SaveMovieCast godoc
+// @Summary      Set a movie's cast
+// @Description  Replace the cast of a movie. Each actor may have a character name and a billing order; an actor or billing order may appear only once (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                   true  "Movie ID"
+// @Param        cast  body      dto.MovieCastRequest  true  "Movie Cast"
+// @Success      200   {object}  dto.Response{data=[]dto.MovieCastResponse}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Failure      500   {object}  dto.Response
+// @Router       /admin/movies/{id}/cast [put]
+func (ctrl CatalogController) SaveMovieCast(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.MovieCastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.SaveMovieCast(c.Request.Context(), id, req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Save Movie Cast Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// GetMovieGenres godoc
+// @Summary      Get a movie's genres
+// @Description  Get the genres of a movie (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Movie ID"
+// @Success      200  {object}  dto.Response{data=[]dto.GenreResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/movies/{id}/genres [get]
+func (ctrl CatalogController) GetMovieGenres(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.GetMovieGenres(c.Request.Context(), id)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Movie Genres Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// SaveMovieGenres godoc
+// @Summary      Set a movie's genres
+// @Description  Replace the genres of a movie (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int                     true  "Movie ID"
+// @Param        genres  body      dto.MovieGenresRequest  true  "Movie Genres"
+// @Success      200     {object}  dto.Response{data=[]dto.GenreResponse}
+// @Failure      400     {object}  dto.Response
+// @Failure      401     {object}  dto.Response
+// @Failure      404     {object}  dto.Response
+// @Failure      500     {object}  dto.Response
+// @Router       /admin/movies/{id}/genres [put]
+func (ctrl CatalogController) SaveMovieGenres(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.MovieGenresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.catalogService.SaveMovieGenres(c.Request.Context(), id, req)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Save Movie Genres Success",
+		Success: true,
+		Data:    data,
+	})
+}
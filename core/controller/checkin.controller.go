@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type CheckinController struct {
+	checkinService *service.CheckinService
+}
+
+func NewCheckinController(checkinService *service.CheckinService) *CheckinController {
+	return &CheckinController{
+		checkinService: checkinService,
+	}
+}
+
+// Checkin godoc
+// @Summary      Check in a ticket at the gate
+// @Description  Redeem a ticket by booking code or QR payload for a schedule. Without seat_ids every seat not admitted yet is admitted. Tickets can only be used once (Requires staff or admin token)
+// @Tags         checkin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        checkin  body      dto.CheckinRequest  true  "Check-in Body"
+// @Success      200      {object}  dto.Response{data=dto.CheckinResponse}
+// @Failure      400      {object}  dto.Response
+// @Failure      401      {object}  dto.Response
+// @Failure      403      {object}  dto.Response
+// @Failure      404      {object}  dto.Response
+// @Failure      409      {object}  dto.Response
+// @Failure      500      {object}  dto.Response
+// @Router       /checkin [post]
+func (ctrl CheckinController) Checkin(c *gin.Context) {
+	var req dto.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.checkinService.Checkin(c.Request.Context(), c.GetInt("user_id"), req)
+	if err != nil {
+		var ticketUsed *apperr.TicketUsedError
+		var invalidSeats *apperr.InvalidSeatsError
+		switch {
+		case errors.Is(err, pkg.ErrInvalidTicket):
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Invalid ticket",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		case errors.As(err, &invalidSeats):
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Invalid seats",
+				Success: false,
+				Error:   invalidSeats.Reason,
+				Data: gin.H{
+					"seat_ids": invalidSeats.SeatIds,
+				},
+			})
+		case errors.Is(err, apperr.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, dto.Response{
+				Msg:     "Ticket not found",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		case errors.As(err, &ticketUsed):
+			c.JSON(http.StatusConflict, dto.Response{
+				Msg:     "Ticket already used",
+				Success: false,
+				Error:   err.Error(),
+				Data: gin.H{
+					"seat_ids":    ticketUsed.SeatIds,
+					"admitted_at": ticketUsed.AdmittedAt,
+				},
+			})
+		case errors.Is(err, apperr.ErrTicketWrongSchedule), errors.Is(err, apperr.ErrOrderNotPaid):
+			c.JSON(http.StatusConflict, dto.Response{
+				Msg:     "Ticket not valid",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.Response{
+				Msg:     "Internal Server Error",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Check-in Success",
+		Success: true,
+		Data:    data,
+	})
+}
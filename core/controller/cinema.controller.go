@@ -0,0 +1,741 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type CinemaController struct {
+	cinemaService *service.CinemaService
+}
+
+func NewCinemaController(cinemaService *service.CinemaService) *CinemaController {
+	return &CinemaController{
+		cinemaService: cinemaService,
+	}
+}
+
+func cinemaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apperr.ErrInvalidSeatLayout), errors.Is(err, apperr.ErrInvalidExt):
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrCityNotFound), errors.Is(err, apperr.ErrCinemaNotFound),
+		errors.Is(err, apperr.ErrAuditoriumNotFound):
+		c.JSON(http.StatusNotFound, dto.Response{
+			Msg:     "Not Found",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrCityNameTaken), errors.Is(err, apperr.ErrCityInUse),
+		errors.Is(err, apperr.ErrCinemaInUse), errors.Is(err, apperr.ErrAuditoriumNameTaken),
+		errors.Is(err, apperr.ErrAuditoriumInUse), errors.Is(err, apperr.ErrSeatsBooked):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Conflict",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	}
+}
+
+// saveCinemaLogo stores an uploaded logo under public/cinema and returns the
+// URL it is served at.
+func saveCinemaLogo(c *gin.Context, logo *multipart.FileHeader) (string, error) {
+	ext := path.Ext(logo.Filename)
+	re := regexp.MustCompile("^[.](jpg|png|jpeg)$")
+	if !re.MatchString(ext) {
+		return "", apperr.ErrInvalidExt
+	}
+
+	filename := fmt.Sprintf("%d_logo_%s", time.Now().UnixNano(), logo.Filename)
+	if err := c.SaveUploadedFile(logo, filepath.Join("public", "cinema", filename)); err != nil {
+		log.Println("Logo Upload Error:", err.Error())
+		return "", errors.New("failed to save logo")
+	}
+	return fmt.Sprintf("/cinema/%s", filename), nil
+}
+
+// GetCities godoc
+// @Summary      List cities
+// @Description  List all cities (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.CityResponse}
+// @Failure      401  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/cities [get]
+func (ctrl CinemaController) GetCities(c *gin.Context) {
+	data, err := ctrl.cinemaService.GetCities(c.Request.Context())
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Cities Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateCity godoc
+// @Summary      Create a city
+// @Description  Create a city cinemas can be placed in (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        city  body      dto.CityRequest  true  "City Body"
+// @Success      201   {object}  dto.Response{data=dto.CityResponse}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      409   {object}  dto.Response
+// @Failure      500   {object}  dto.Response
+// @Router       /admin/cities [post]
+func (ctrl CinemaController) CreateCity(c *gin.Context) {
+	var req dto.CityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.CreateCity(c.Request.Context(), req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create City Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateCity godoc
+// @Summary      Update a city
+// @Description  Rename a city (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int              true  "City ID"
+// @Param        city  body      dto.CityRequest  true  "City Body"
+// @Success      200   {object}  dto.Response{data=dto.CityResponse}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Failure      409   {object}  dto.Response
+// @Failure      500   {object}  dto.Response
+// @Router       /admin/cities/{id} [put]
+func (ctrl CinemaController) UpdateCity(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.CityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.UpdateCity(c.Request.Context(), id, req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update City Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteCity godoc
+// @Summary      Delete a city
+// @Description  Delete a city that has no cinemas (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "City ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/cities/{id} [delete]
+func (ctrl CinemaController) DeleteCity(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.cinemaService.DeleteCity(c.Request.Context(), id); err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete City Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetCinemas godoc
+// @Summary      List cinemas
+// @Description  List cinemas with their city and seat count, optionally filtered by city (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        city_id  query     int  false  "City ID"
+// @Success      200      {object}  dto.Response{data=[]dto.CinemaResponse}
+// @Failure      400      {object}  dto.Response
+// @Failure      401      {object}  dto.Response
+// @Failure      500      {object}  dto.Response
+// @Router       /admin/cinemas [get]
+func (ctrl CinemaController) GetCinemas(c *gin.Context) {
+	cityId, err := optionalIntQuery(c, "city_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid city_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.GetCinemas(c.Request.Context(), cityId)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Cinemas Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// GetCinema godoc
+// @Summary      Get a cinema
+// @Description  Get a cinema by ID (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Cinema ID"
+// @Success      200  {object}  dto.Response{data=dto.CinemaResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/cinemas/{id} [get]
+func (ctrl CinemaController) GetCinema(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.GetCinema(c.Request.Context(), id)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Cinema Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateCinema godoc
+// @Summary      Create a cinema
+// @Description  Create a cinema in a city (Requires admin token)
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name      formData  string  true   "Cinema Name"
+// @Param        location  formData  string  true   "Cinema Address"
+// @Param        city_id   formData  int     true   "City ID"
+// @Param        logo      formData  file    false  "Logo Image"
+// @Success      201       {object}  dto.Response{data=dto.CinemaResponse}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      500       {object}  dto.Response
+// @Router       /admin/cinemas [post]
+func (ctrl CinemaController) CreateCinema(c *gin.Context) {
+	var req dto.CreateCinemaRequest
+	if err := c.ShouldBindWith(&req, binding.FormMultipart); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if req.Logo != nil {
+		logoPath, err := saveCinemaLogo(c, req.Logo)
+		if err != nil {
+			cinemaError(c, err)
+			return
+		}
+		req.LogoUrl = &logoPath
+	}
+
+	data, err := ctrl.cinemaService.CreateCinema(c.Request.Context(), req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Cinema Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateCinema godoc
+// @Summary      Update a cinema
+// @Description  Update cinema details. Fields that are left out keep their value (Requires admin token)
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int     true   "Cinema ID"
+// @Param        name      formData  string  false  "Cinema Name"
+// @Param        location  formData  string  false  "Cinema Address"
+// @Param        city_id   formData  int     false  "City ID"
+// @Param        logo      formData  file    false  "Logo Image"
+// @Success      200       {object}  dto.Response{data=dto.CinemaResponse}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      500       {object}  dto.Response
+// @Router       /admin/cinemas/{id} [patch]
+func (ctrl CinemaController) UpdateCinema(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.UpdateCinemaRequest
+	if err := c.ShouldBindWith(&req, binding.FormMultipart); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if req.Logo != nil {
+		logoPath, err := saveCinemaLogo(c, req.Logo)
+		if err != nil {
+			cinemaError(c, err)
+			return
+		}
+		req.LogoUrl = &logoPath
+	}
+
+	data, err := ctrl.cinemaService.UpdateCinema(c.Request.Context(), id, req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Cinema Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteCinema godoc
+// @Summary      Delete a cinema
+// @Description  Delete a cinema with its auditoriums and seats. Cinemas that have schedules can not be deleted (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Cinema ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/cinemas/{id} [delete]
+func (ctrl CinemaController) DeleteCinema(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.cinemaService.DeleteCinema(c.Request.Context(), id); err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Cinema Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetAuditoriums godoc
+// @Summary      List a cinema's auditoriums
+// @Description  List the auditoriums (studios) of a cinema with their seat count (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Cinema ID"
+// @Success      200  {object}  dto.Response{data=[]dto.AuditoriumResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/cinemas/{id}/auditoriums [get]
+func (ctrl CinemaController) GetAuditoriums(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.GetAuditoriums(c.Request.Context(), id)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Auditoriums Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateAuditorium godoc
+// @Summary      Create an auditorium
+// @Description  Add an auditorium (studio) to a cinema. Names are unique within a cinema (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int                    true  "Cinema ID"
+// @Param        auditorium  body      dto.AuditoriumRequest  true  "Auditorium Body"
+// @Success      201         {object}  dto.Response{data=dto.AuditoriumResponse}
+// @Failure      400         {object}  dto.Response
+// @Failure      401         {object}  dto.Response
+// @Failure      404         {object}  dto.Response
+// @Failure      409         {object}  dto.Response
+// @Failure      500         {object}  dto.Response
+// @Router       /admin/cinemas/{id}/auditoriums [post]
+func (ctrl CinemaController) CreateAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.AuditoriumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.CreateAuditorium(c.Request.Context(), id, req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Auditorium Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateAuditorium godoc
+// @Summary      Rename an auditorium
+// @Description  Rename an auditorium (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int                    true  "Auditorium ID"
+// @Param        auditorium  body      dto.AuditoriumRequest  true  "Auditorium Body"
+// @Success      200         {object}  dto.Response{data=dto.AuditoriumResponse}
+// @Failure      400         {object}  dto.Response
+// @Failure      401         {object}  dto.Response
+// @Failure      404         {object}  dto.Response
+// @Failure      409         {object}  dto.Response
+// @Failure      500         {object}  dto.Response
+// @Router       /admin/auditoriums/{id} [put]
+func (ctrl CinemaController) UpdateAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.AuditoriumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.UpdateAuditorium(c.Request.Context(), id, req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Auditorium Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteAuditorium godoc
+// @Summary      Delete an auditorium
+// @Description  Delete an auditorium and its seats. Auditoriums that have schedules can not be deleted (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Auditorium ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/auditoriums/{id} [delete]
+func (ctrl CinemaController) DeleteAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.cinemaService.DeleteAuditorium(c.Request.Context(), id); err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Auditorium Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetSeatLayout godoc
+// @Summary      Get an auditorium's seat layout
+// @Description  Get the seats of an auditorium grouped by row. Missing seat numbers are gaps (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Auditorium ID"
+// @Success      200  {object}  dto.Response{data=dto.SeatLayoutResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/auditoriums/{id}/seats [get]
+func (ctrl CinemaController) GetSeatLayout(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.GetSeatLayout(c.Request.Context(), id)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Seat Layout Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// SaveSeatLayout godoc
+// @Summary      Define an auditorium's seat layout
+// @Description  Replace the seat grid of an auditorium. Each row has a number of seats, a default seat type, optional sections with another seat type and optional gaps (seat numbers left empty, e.g. for an aisle). Seats that keep their row and number keep their ID; removing seats that appear in orders is rejected (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int                    true  "Auditorium ID"
+// @Param        layout  body      dto.SeatLayoutRequest  true  "Seat Layout"
+// @Success      200     {object}  dto.Response{data=dto.SeatLayoutResponse}
+// @Failure      400     {object}  dto.Response
+// @Failure      401     {object}  dto.Response
+// @Failure      404     {object}  dto.Response
+// @Failure      409     {object}  dto.Response
+// @Failure      500     {object}  dto.Response
+// @Router       /admin/auditoriums/{id}/seats [put]
+func (ctrl CinemaController) SaveSeatLayout(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.SeatLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.cinemaService.SaveSeatLayout(c.Request.Context(), id, req)
+	if err != nil {
+		cinemaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Save Seat Layout Success",
+		Success: true,
+		Data:    data,
+	})
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginThrottleController struct {
+	loginThrottleService *service.LoginThrottleService
+}
+
+func NewLoginThrottleController(loginThrottleService *service.LoginThrottleService) *LoginThrottleController {
+	return &LoginThrottleController{
+		loginThrottleService: loginThrottleService,
+	}
+}
+
+// UnlockLogin godoc
+// @Summary      Unlock login
+// @Description  Lift the login backoff or lockout of an email, an IP address or both, and forget their failed attempts (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        unlock  body      dto.UnlockLoginRequest  true  "Email and/or IP Address"
+// @Success      200     {object}  dto.Response
+// @Failure      400     {object}  dto.Response
+// @Failure      401     {object}  dto.Response
+// @Failure      403     {object}  dto.Response
+// @Failure      500     {object}  dto.Response
+// @Router       /admin/login-lockouts/unlock [post]
+func (ctrl LoginThrottleController) UnlockLogin(c *gin.Context) {
+	var req dto.UnlockLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.loginThrottleService.Unlock(c.Request.Context(), req); err != nil {
+		if errors.Is(err, apperr.ErrInvalidUnlockRequest) {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Unlock Login Success",
+		Success: true,
+		Data:    nil,
+	})
+}
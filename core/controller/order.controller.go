@@ -1,11 +1,17 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
 	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,9 +31,9 @@ func NewOrderController(orderService *service.OrderService) *OrderController {
 // @Tags         orders
 // @Accept       json
 // @Produce      json
-// @Param        id    path      int     true  "Movie ID"
-// @Param        date  query     string  false "Show Date (YYYY-MM-DD)"
-// @Param        city  query     string  false "City Name"
+// @Param        id    path      int     true   "Movie ID"
+// @Param        date  query     string  false  "Show Date (YYYY-MM-DD)"
+// @Param        city  query     string  false  "City Name"
 // @Success      200   {object}  dto.Response{data=[]dto.GetSchedules}
 // @Failure      400   {object}  dto.Response
 // @Failure      500   {object}  dto.Response
@@ -85,7 +91,7 @@ func (ctrl OrderController) GetSchedules(c *gin.Context) {
 
 // GetSeats godoc
 // @Summary      Get seats for a schedule
-// @Description  Get list of seats status (sold/available) for a specific schedule ID
+// @Description  Get list of seats status (sold/held/available) for a specific schedule ID
 // @Tags         orders
 // @Accept       json
 // @Produce      json
@@ -133,18 +139,109 @@ func (ctrl OrderController) GetSeats(c *gin.Context) {
 	})
 }
 
+// seatStreamHeartbeat keeps idle seat map streams from being closed by
+// proxies.
+const seatStreamHeartbeat = 25 * time.Second
+
+// StreamSeats godoc
+// @Summary      Stream seat map updates
+// @Description  Server-Sent Events stream for a schedule. Sends a "snapshot" event with the full seat map, then a "seat" event with {schedule_id, seat_ids, status} whenever seats are held, sold or released, and a "ping" event to keep the connection alive. A client that falls behind is disconnected and should reconnect for a new snapshot. Each client address may keep at most 5 streams open
+// @Tags         orders
+// @Produce      text/event-stream
+// @Param        id   path      int  true  "Schedule ID"
+// @Success      200  {object}  dto.SeatEvent
+// @Failure      400  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      429  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /orders/seats/{id}/stream [get]
+func (ctrl OrderController) StreamSeats(c *gin.Context) {
+	idParam := c.Param("id")
+	scheduleId, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid schedule_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	events, err := ctrl.orderService.SubscribeSeatEvents(ctx, scheduleId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	snapshot, err := ctrl.orderService.GetSeatsByScheduleID(ctx, scheduleId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	if len(snapshot) == 0 {
+		c.JSON(http.StatusNotFound, dto.Response{
+			Msg:     "Data not found",
+			Success: false,
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("snapshot", snapshot)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(seatStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("seat", event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
 // CreateOrder godoc
 // @Summary      Create a new order
-// @Description  Create a new ticket order for a user (Requires user token)
+// @Description  Create a new ticket order for a user and start its payment. An optional promo_code is applied to the seat subtotal, then redeem_points loyalty points are taken off the rest (Requires user token)
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        order  body      dto.CreateOrderRequest  true  "Order Body"
-// @Success      201    {object}  dto.Response
-// @Failure      401    {object}  dto.Response
-// @Failure      400    {object}  dto.Response
-// @Failure      500    {object}  dto.Response
+// @Param        order            body      dto.CreateOrderRequest  true   "Order Body"
+// @Param        Idempotency-Key  header    string                  false  "Retry-safe request key"
+// @Success      201              {object}  dto.Response{data=dto.CreateOrderResponse}
+// @Failure      401              {object}  dto.Response
+// @Failure      400              {object}  dto.Response
+// @Failure      403              {object}  dto.Response
+// @Failure      404              {object}  dto.Response
+// @Failure      409              {object}  dto.Response
+// @Failure      500              {object}  dto.Response
 // @Router       /orders [post]
 func (ctrl OrderController) CreateOrder(c *gin.Context) {
 	userId, exist := c.Get("user_id")
@@ -180,8 +277,62 @@ func (ctrl OrderController) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	id, bookingCode, createdAt, err := ctrl.orderService.CreateOrder(c.Request.Context(), userIdInt, req)
+	data, err := ctrl.orderService.CreateOrder(c.Request.Context(), userIdInt, req)
 	if err != nil {
+		if errors.Is(err, apperr.ErrNoSeatsSelected) || errors.Is(err, apperr.ErrPaymentMethodNotFound) || errors.Is(err, apperr.ErrInsufficientPoints) {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		if errors.Is(err, apperr.ErrScheduleNotFound) {
+			c.JSON(http.StatusNotFound, dto.Response{
+				Msg:     "Not Found",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		var invalidSeats *apperr.InvalidSeatsError
+		if errors.As(err, &invalidSeats) {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Invalid seats",
+				Success: false,
+				Error:   invalidSeats.Reason,
+				Data: gin.H{
+					"seat_ids": invalidSeats.SeatIds,
+				},
+			})
+			return
+		}
+		var voucherErr *apperr.VoucherError
+		if errors.As(err, &voucherErr) {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Promo code not applicable",
+				Success: false,
+				Error:   voucherErr.Reason,
+				Data: gin.H{
+					"promo_code": voucherErr.Code,
+				},
+			})
+			return
+		}
+		var seatTaken *apperr.SeatTakenError
+		if errors.As(err, &seatTaken) {
+			c.JSON(http.StatusConflict, dto.Response{
+				Msg:     "Seat already taken",
+				Success: false,
+				Error:   err.Error(),
+				Data: gin.H{
+					"seat_ids": seatTaken.SeatIds,
+				},
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.Response{
 			Msg:     "Internal Server Error",
 			Success: false,
@@ -194,27 +345,27 @@ func (ctrl OrderController) CreateOrder(c *gin.Context) {
 	c.JSON(http.StatusCreated, dto.Response{
 		Msg:     "Create Order Success",
 		Success: true,
-		Data: gin.H{
-			"id":           id,
-			"booking_code": bookingCode,
-			"created_at":   createdAt,
-		},
+		Data:    data,
 	})
 }
 
 // UpdatePaymentStatus godoc
 // @Summary      Update order payment status
-// @Description  Update the payment status of an order (Requires admin token)
+// @Description  Move an order to a new payment status (pending -> paid/expired/cancelled, paid -> refunded). Cancelling and refunding work like the cancel endpoints: owners may only cancel their own orders until the cancellation cutoff, and refunds go back through the payment provider. Admins may apply any valid transition; marking an order paid closes its open payment attempts, and a capture reported for them later is refunded
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id      path      int                     true  "Order ID"
-// @Param        status  body      dto.UpdateOrderRequest  true  "Status Body"
-// @Success      200     {object}  dto.Response
-// @Failure      401     {object}  dto.Response
-// @Failure      400     {object}  dto.Response
-// @Failure      500     {object}  dto.Response
+// @Param        id               path      int                     true   "Order ID"
+// @Param        status           body      dto.UpdateOrderRequest  true   "Status Body"
+// @Param        Idempotency-Key  header    string                  false  "Retry-safe request key"
+// @Success      200              {object}  dto.Response
+// @Failure      401              {object}  dto.Response
+// @Failure      400              {object}  dto.Response
+// @Failure      403              {object}  dto.Response
+// @Failure      404              {object}  dto.Response
+// @Failure      409              {object}  dto.Response
+// @Failure      500              {object}  dto.Response
 // @Router       /orders/{id} [patch]
 func (ctrl OrderController) UpdatePaymentStatus(c *gin.Context) {
 	idParam := c.Param("id")
@@ -240,10 +391,43 @@ func (ctrl OrderController) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
-	err = ctrl.orderService.UpdatePaymentStatus(c.Request.Context(), orderId, req.PaymentStatus)
+	userId := c.GetInt("user_id")
+	role := c.GetString("role")
+
+	err = ctrl.orderService.UpdatePaymentStatus(c.Request.Context(), userId, role, orderId, req.PaymentStatus)
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Payment Status Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// GetOrderDetail godoc
+// @Summary      Get order detail
+// @Description  Get the full e-ticket of an order: movie, cinema, show time, seats, price breakdown and payment. Only the owner or an admin can read it
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.Response{data=dto.OrderDetailResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /orders/{id} [get]
+func (ctrl OrderController) GetOrderDetail(c *gin.Context) {
+	idParam := c.Param("id")
+	orderId, err := strconv.Atoi(idParam)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Msg:     "Internal Server Error",
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid order_id parameter",
 			Success: false,
 			Error:   err.Error(),
 			Data:    nil,
@@ -251,9 +435,227 @@ func (ctrl OrderController) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
+	data, err := ctrl.orderService.GetOrderDetail(c.Request.Context(), c.GetInt("user_id"), c.GetString("role"), orderId)
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
-		Msg:     "Update Payment Status Success",
+		Msg:     "Get Order Detail Success",
 		Success: true,
-		Data:    nil,
+		Data:    data,
 	})
 }
+
+// GetTicketQRCode godoc
+// @Summary      Get ticket QR code
+// @Description  Get a PNG QR code encoding the signed ticket payload (booking code, order id and HMAC) of a paid order. Only the owner or an admin can read it
+// @Tags         orders
+// @Produce      png
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {file}    binary
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /orders/{id}/ticket.png [get]
+func (ctrl OrderController) GetTicketQRCode(c *gin.Context) {
+	idParam := c.Param("id")
+	orderId, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid order_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	png, err := ctrl.orderService.GetTicketQRCode(c.Request.Context(), c.GetInt("user_id"), c.GetString("role"), orderId)
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// GetTicketPDF godoc
+// @Summary      Download ticket PDF
+// @Description  Download the printable e-ticket (movie, poster, cinema, seats, show time, booking code and QR) of a paid order owned by the caller
+// @Tags         orders
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {file}    binary
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /orders/{id}/ticket.pdf [get]
+func (ctrl OrderController) GetTicketPDF(c *gin.Context) {
+	idParam := c.Param("id")
+	orderId, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid order_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	pdf, err := ctrl.orderService.GetTicketPDF(c.Request.Context(), c.GetInt("user_id"), orderId)
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tickitz-%d.pdf"`, orderId))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// CancelOrder godoc
+// @Summary      Cancel an order
+// @Description  Cancel a pending order or refund a paid one. Only allowed until ORDER_CANCEL_CUTOFF before the show starts (Requires user token)
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.Response{data=dto.CancelOrderResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /orders/{id}/cancel [post]
+func (ctrl OrderController) CancelOrder(c *gin.Context) {
+	ctrl.cancelOrder(c, false)
+}
+
+// CancelOrderAdmin godoc
+// @Summary      Cancel an order (Admin)
+// @Description  Cancel a pending order or refund a paid one regardless of the cancellation cutoff (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.Response{data=dto.CancelOrderResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/orders/{id}/cancel [post]
+func (ctrl OrderController) CancelOrderAdmin(c *gin.Context) {
+	ctrl.cancelOrder(c, true)
+}
+
+func (ctrl OrderController) cancelOrder(c *gin.Context, asAdmin bool) {
+	idParam := c.Param("id")
+	orderId, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid order_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.orderService.CancelOrder(c.Request.Context(), c.GetInt("user_id"), orderId, asAdmin)
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Cancel Order Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// orderStatusError writes the response for errors returned by the order
+// status state machine.
+func orderStatusError(c *gin.Context, err error) {
+	var invalidTransition *apperr.InvalidTransitionError
+	switch {
+	case errors.Is(err, apperr.ErrInvalidPaymentStatus),
+		errors.Is(err, apperr.ErrInvalidPaymentPayload),
+		errors.Is(err, apperr.ErrPaymentAmountMismatch):
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, pkg.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Msg:     "Unauthorized",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrOrderNotFound), errors.Is(err, apperr.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, dto.Response{
+			Msg:     "Not Found",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrOrderForbidden), errors.Is(err, apperr.ErrStatusNotAllowed):
+		c.JSON(http.StatusForbidden, dto.Response{
+			Msg:     "Forbidden Access",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrOrderNotPaid):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Order not paid",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrCancellationClosed):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Cancellation closed",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.As(err, &invalidTransition):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Invalid status transition",
+			Success: false,
+			Error:   err.Error(),
+			Data: gin.H{
+				"from": invalidTransition.From,
+				"to":   invalidTransition.To,
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	}
+}
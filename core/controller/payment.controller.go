@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentController struct {
+	orderService *service.OrderService
+}
+
+func NewPaymentController(orderService *service.OrderService) *PaymentController {
+	return &PaymentController{
+		orderService: orderService,
+	}
+}
+
+// Webhook godoc
+// @Summary      Payment gateway webhook
+// @Description  Receive a payment result from the gateway. The raw body must be signed with HMAC-SHA256 using the webhook secret and the hex digest sent in the X-Signature header
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        X-Signature  header    string  true  "HMAC-SHA256 signature of the body"
+// @Success      200          {object}  dto.Response
+// @Failure      400          {object}  dto.Response
+// @Failure      401          {object}  dto.Response
+// @Failure      404          {object}  dto.Response
+// @Failure      409          {object}  dto.Response
+// @Failure      500          {object}  dto.Response
+// @Router       /payments/webhook [post]
+func (ctrl PaymentController) Webhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	err = ctrl.orderService.HandlePaymentWebhook(c.Request.Context(), payload, c.GetHeader("X-Signature"))
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Webhook Processed",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// SimulatePayment godoc
+// @Summary      Simulate a payment result
+// @Description  Complete a payment made with the simulated provider by sending a signed webhook for it. Only available when PAYMENT_PROVIDER is simulated
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        reference  path      string                      true  "Payment Reference"
+// @Param        status     body      dto.SimulatePaymentRequest  true  "Payment result (paid or failed)"
+// @Success      200        {object}  dto.Response
+// @Failure      400        {object}  dto.Response
+// @Failure      404        {object}  dto.Response
+// @Failure      409        {object}  dto.Response
+// @Failure      500        {object}  dto.Response
+// @Router       /payments/simulate/{reference} [post]
+func (ctrl PaymentController) SimulatePayment(c *gin.Context) {
+	var req dto.SimulatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	err := ctrl.orderService.SimulatePayment(c.Request.Context(), c.Param("reference"), req.Status)
+	if err != nil {
+		orderStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Simulate Payment Success",
+		Success: true,
+		Data:    nil,
+	})
+}
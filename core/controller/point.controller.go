@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PointController struct {
+	pointService *service.PointService
+}
+
+func NewPointController(pointService *service.PointService) *PointController {
+	return &PointController{
+		pointService: pointService,
+	}
+}
+
+// GetPoints godoc
+// @Summary      Get loyalty points
+// @Description  Get the user's loyalty point balance and ledger of earned, redeemed and reversed points (Requires user token)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=dto.PointsResponse}
+// @Failure      401  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /user/points [get]
+func (ctrl PointController) GetPoints(c *gin.Context) {
+	data, err := ctrl.pointService.GetPoints(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Points Success",
+		Success: true,
+		Data:    data,
+	})
+}
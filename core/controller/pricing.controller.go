@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PricingController struct {
+	pricingService *service.PricingService
+}
+
+func NewPricingController(pricingService *service.PricingService) *PricingController {
+	return &PricingController{
+		pricingService: pricingService,
+	}
+}
+
+func optionalIntQuery(c *gin.Context, key string) (*int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// GetSeatTypePrices godoc
+// @Summary      List seat type prices
+// @Description  List seat type pricing rules, optionally filtered by cinema or schedule (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cinema_id    query     int  false  "Cinema ID"
+// @Param        schedule_id  query     int  false  "Schedule ID"
+// @Success      200          {object}  dto.Response{data=[]dto.SeatTypePriceResponse}
+// @Failure      400          {object}  dto.Response
+// @Failure      401          {object}  dto.Response
+// @Failure      500          {object}  dto.Response
+// @Router       /admin/seat-prices [get]
+func (ctrl PricingController) GetSeatTypePrices(c *gin.Context) {
+	cinemaId, err := optionalIntQuery(c, "cinema_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid cinema_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    []any{},
+		})
+		return
+	}
+	scheduleId, err := optionalIntQuery(c, "schedule_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid schedule_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    []any{},
+		})
+		return
+	}
+
+	data, err := ctrl.pricingService.GetSeatTypePrices(c.Request.Context(), cinemaId, scheduleId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    []any{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Seat Type Prices Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// SaveSeatTypePrice godoc
+// @Summary      Set a seat type price
+// @Description  Create or replace the pricing rule of a seat type for a cinema or a single schedule. Seat price = round(schedule price * multiplier) + surcharge; schedule rules override cinema rules (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        price  body      dto.SeatTypePriceRequest  true  "Seat Type Price Body"
+// @Success      200    {object}  dto.Response{data=dto.SeatTypePriceResponse}
+// @Failure      400    {object}  dto.Response
+// @Failure      401    {object}  dto.Response
+// @Failure      404    {object}  dto.Response
+// @Failure      500    {object}  dto.Response
+// @Router       /admin/seat-prices [put]
+func (ctrl PricingController) SaveSeatTypePrice(c *gin.Context) {
+	var req dto.SeatTypePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.pricingService.SaveSeatTypePrice(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrInvalidPriceScope):
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		case errors.Is(err, apperr.ErrPriceScopeNotFound):
+			c.JSON(http.StatusNotFound, dto.Response{
+				Msg:     "Not Found",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.Response{
+				Msg:     "Internal Server Error",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Save Seat Type Price Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteSeatTypePrice godoc
+// @Summary      Delete a seat type price
+// @Description  Delete a seat type pricing rule (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Seat Type Price ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/seat-prices/{id} [delete]
+func (ctrl PricingController) DeleteSeatTypePrice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.pricingService.DeleteSeatTypePrice(c.Request.Context(), id); err != nil {
+		if errors.Is(err, apperr.ErrSeatTypePriceNotFound) {
+			c.JSON(http.StatusNotFound, dto.Response{
+				Msg:     "Not Found",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Seat Type Price Success",
+		Success: true,
+		Data:    nil,
+	})
+}
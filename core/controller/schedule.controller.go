@@ -0,0 +1,320 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleController struct {
+	scheduleService *service.ScheduleService
+}
+
+func NewScheduleController(scheduleService *service.ScheduleService) *ScheduleController {
+	return &ScheduleController{
+		scheduleService: scheduleService,
+	}
+}
+
+func scheduleError(c *gin.Context, err error) {
+	var overlapErr *apperr.ScheduleOverlapError
+	switch {
+	case errors.Is(err, apperr.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrScheduleNotFound), errors.Is(err, apperr.ErrMovieNotFound),
+		errors.Is(err, apperr.ErrCinemaNotFound):
+		c.JSON(http.StatusNotFound, dto.Response{
+			Msg:     "Not Found",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.As(err, &overlapErr), errors.Is(err, apperr.ErrScheduleHasPaidOrders),
+		errors.Is(err, apperr.ErrScheduleHasPendingOrders):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Conflict",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	}
+}
+
+func optionalDateQuery(c *gin.Context, key string) (*string, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// GetSchedules godoc
+// @Summary      List schedules
+// @Description  List schedules with their paid order count, optionally filtered by movie, cinema, auditorium and show date range (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        movie_id       query     int     false  "Movie ID"
+// @Param        cinema_id      query     int     false  "Cinema ID"
+// @Param        auditorium_id  query     int     false  "Auditorium ID"
+// @Param        date_from      query     string  false  "First show date (YYYY-MM-DD)"
+// @Param        date_to        query     string  false  "Last show date (YYYY-MM-DD)"
+// @Success      200            {object}  dto.Response{data=[]dto.ScheduleResponse}
+// @Failure      400            {object}  dto.Response
+// @Failure      401            {object}  dto.Response
+// @Failure      500            {object}  dto.Response
+// @Router       /admin/schedules [get]
+func (ctrl ScheduleController) GetSchedules(c *gin.Context) {
+	var filter model.ScheduleFilter
+	var err error
+	if filter.MovieId, err = optionalIntQuery(c, "movie_id"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid movie_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	if filter.CinemaId, err = optionalIntQuery(c, "cinema_id"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid cinema_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	if filter.AuditoriumId, err = optionalIntQuery(c, "auditorium_id"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid auditorium_id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	if filter.DateFrom, err = optionalDateQuery(c, "date_from"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid date_from parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	if filter.DateTo, err = optionalDateQuery(c, "date_to"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid date_to parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.scheduleService.GetSchedules(c.Request.Context(), filter)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Schedules Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// GetSchedule godoc
+// @Summary      Get a schedule
+// @Description  Get a schedule by ID (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Schedule ID"
+// @Success      200  {object}  dto.Response{data=dto.ScheduleResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/schedules/{id} [get]
+func (ctrl ScheduleController) GetSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.scheduleService.GetSchedule(c.Request.Context(), id)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Schedule Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateSchedules godoc
+// @Summary      Create schedules
+// @Description  Create a show of a movie in an auditorium at every time slot on every day of a date range (at most 62 days). Shows may not overlap other shows in the auditorium, counting the movie duration plus the cleaning buffer; if one does, nothing is created (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        schedules  body      dto.CreateSchedulesRequest  true  "Schedules Body"
+// @Success      201        {object}  dto.Response{data=[]dto.ScheduleResponse}
+// @Failure      400        {object}  dto.Response
+// @Failure      401        {object}  dto.Response
+// @Failure      404        {object}  dto.Response
+// @Failure      409        {object}  dto.Response
+// @Failure      500        {object}  dto.Response
+// @Router       /admin/schedules [post]
+func (ctrl ScheduleController) CreateSchedules(c *gin.Context) {
+	var req dto.CreateSchedulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.scheduleService.CreateSchedules(c.Request.Context(), req)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Schedules Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateSchedule godoc
+// @Summary      Update a schedule
+// @Description  Replace the movie, auditorium, show time and price of a schedule. Schedules with paid orders can not be changed, and the movie and auditorium of schedules with pending orders stay fixed (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                        true  "Schedule ID"
+// @Param        schedule  body      dto.UpdateScheduleRequest  true  "Schedule Body"
+// @Success      200       {object}  dto.Response{data=dto.ScheduleResponse}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      409       {object}  dto.Response
+// @Failure      500       {object}  dto.Response
+// @Router       /admin/schedules/{id} [put]
+func (ctrl ScheduleController) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.UpdateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.scheduleService.UpdateSchedule(c.Request.Context(), id, req)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Schedule Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteSchedule godoc
+// @Summary      Delete a schedule
+// @Description  Delete a schedule along with its cancelled, expired and failed orders. Schedules with paid or pending orders can not be deleted; pending orders expire on their own (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Schedule ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/schedules/{id} [delete]
+func (ctrl ScheduleController) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.scheduleService.DeleteSchedule(c.Request.Context(), id); err != nil {
+		scheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Schedule Success",
+		Success: true,
+		Data:    nil,
+	})
+}
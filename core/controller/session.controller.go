@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	sessionService *service.SessionService
+}
+
+func NewSessionController(sessionService *service.SessionService) *SessionController {
+	return &SessionController{
+		sessionService: sessionService,
+	}
+}
+
+// GetSessions godoc
+// @Summary      List active sessions
+// @Description  List the devices the user is logged in on, with their user agent, IP address and last seen time. The session of the current token is marked as current (Requires user token)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.SessionResponse}
+// @Failure      401  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /user/sessions [get]
+func (ctrl SessionController) GetSessions(c *gin.Context) {
+	data, err := ctrl.sessionService.GetSessions(c.Request.Context(), c.GetInt("user_id"), c.GetInt("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Sessions Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a session
+// @Description  Log one of the user's sessions out, revoking its access and refresh tokens (Requires user token)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Session ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /user/sessions/{id} [delete]
+func (ctrl SessionController) RevokeSession(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   "Invalid id parameter",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.sessionService.RevokeSession(c.Request.Context(), c.GetInt("user_id"), id); err != nil {
+		if errors.Is(err, apperr.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, dto.Response{
+				Msg:     "Not Found",
+				Success: false,
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Revoke Session Success",
+		Success: true,
+		Data:    nil,
+	})
+}
+
+// RevokeAllSessions godoc
+// @Summary      Log out everywhere
+// @Description  Revoke every session of the user, the current one included (Requires user token)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /user/sessions [delete]
+func (ctrl SessionController) RevokeAllSessions(c *gin.Context) {
+	if err := ctrl.sessionService.RevokeAllSessions(c.Request.Context(), c.GetInt("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   "internal server error",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Revoke All Sessions Success",
+		Success: true,
+		Data:    nil,
+	})
+}
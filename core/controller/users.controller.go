@@ -131,7 +131,7 @@ func (u UserController) GetHistory(c *gin.Context) {
 
 // UpdatePassword godoc
 // @Summary      Update user password
-// @Description  Change user password (Requires user token)
+// @Description  Change user password. Every session of the user is logged out, the current one included (Requires user token)
 // @Tags         user
 // @Accept       json
 // @Produce      json
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherController struct {
+	voucherService *service.VoucherService
+}
+
+func NewVoucherController(voucherService *service.VoucherService) *VoucherController {
+	return &VoucherController{
+		voucherService: voucherService,
+	}
+}
+
+func voucherError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apperr.ErrInvalidVoucherRule):
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrVoucherNotFound):
+		c.JSON(http.StatusNotFound, dto.Response{
+			Msg:     "Not Found",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, apperr.ErrVoucherCodeTaken), errors.Is(err, apperr.ErrVoucherInUse):
+		c.JSON(http.StatusConflict, dto.Response{
+			Msg:     "Conflict",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+	}
+}
+
+// GetVouchers godoc
+// @Summary      List vouchers
+// @Description  List all promo codes (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.VoucherResponse}
+// @Failure      401  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/vouchers [get]
+func (ctrl VoucherController) GetVouchers(c *gin.Context) {
+	data, err := ctrl.voucherService.GetVouchers(c.Request.Context())
+	if err != nil {
+		voucherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Vouchers Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// GetVoucher godoc
+// @Summary      Get a voucher
+// @Description  Get a promo code by ID (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Voucher ID"
+// @Success      200  {object}  dto.Response{data=dto.VoucherResponse}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/vouchers/{id} [get]
+func (ctrl VoucherController) GetVoucher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.voucherService.GetVoucher(c.Request.Context(), id)
+	if err != nil {
+		voucherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Get Voucher Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// CreateVoucher godoc
+// @Summary      Create a voucher
+// @Description  Create a percentage or fixed amount promo code with a validity window, optional usage limits and optional movie, cinema or city restriction (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        voucher  body      dto.VoucherRequest  true  "Voucher Body"
+// @Success      201      {object}  dto.Response{data=dto.VoucherResponse}
+// @Failure      400      {object}  dto.Response
+// @Failure      401      {object}  dto.Response
+// @Failure      409      {object}  dto.Response
+// @Failure      500      {object}  dto.Response
+// @Router       /admin/vouchers [post]
+func (ctrl VoucherController) CreateVoucher(c *gin.Context) {
+	var req dto.VoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.voucherService.CreateVoucher(c.Request.Context(), req)
+	if err != nil {
+		voucherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Msg:     "Create Voucher Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// UpdateVoucher godoc
+// @Summary      Update a voucher
+// @Description  Replace a promo code's settings (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                 true  "Voucher ID"
+// @Param        voucher  body      dto.VoucherRequest  true  "Voucher Body"
+// @Success      200      {object}  dto.Response{data=dto.VoucherResponse}
+// @Failure      400      {object}  dto.Response
+// @Failure      401      {object}  dto.Response
+// @Failure      404      {object}  dto.Response
+// @Failure      409      {object}  dto.Response
+// @Failure      500      {object}  dto.Response
+// @Router       /admin/vouchers/{id} [put]
+func (ctrl VoucherController) UpdateVoucher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	var req dto.VoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Bad Request",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	data, err := ctrl.voucherService.UpdateVoucher(c.Request.Context(), id, req)
+	if err != nil {
+		voucherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Update Voucher Success",
+		Success: true,
+		Data:    data,
+	})
+}
+
+// DeleteVoucher godoc
+// @Summary      Delete a voucher
+// @Description  Delete a promo code that has never been used. Used codes must be deactivated instead (Requires admin token)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Voucher ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Failure      500  {object}  dto.Response
+// @Router       /admin/vouchers/{id} [delete]
+func (ctrl VoucherController) DeleteVoucher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Msg:     "Invalid id parameter",
+			Success: false,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := ctrl.voucherService.DeleteVoucher(c.Request.Context(), id); err != nil {
+		voucherError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Msg:     "Delete Voucher Success",
+		Success: true,
+		Data:    nil,
+	})
+}
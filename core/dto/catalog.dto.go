@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+type PersonRequest struct {
+	Name string `json:"name" binding:"required" example:"Christopher Nolan"`
+}
+
+type DirectorResponse struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	MovieCount int       `json:"movie_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ActorResponse struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	MovieCount int       `json:"movie_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GenreRequest struct {
+	Name string `json:"name" binding:"required" example:"Action"`
+}
+
+type GenreResponse struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	MovieCount int    `json:"movie_count"`
+}
+
+// MovieCastRequest replaces the whole cast of a movie; an empty list removes
+// it.
+type MovieCastRequest struct {
+	Cast []MovieCastMember `json:"cast" binding:"required,dive"`
+}
+
+type MovieCastMember struct {
+	ActorId       int    `json:"actor_id" binding:"required"`
+	CharacterName string `json:"character_name" example:"Cobb"`
+	BillingOrder  *int   `json:"billing_order" binding:"omitempty,gt=0" example:"1"`
+}
+
+type MovieCastResponse struct {
+	ActorId       int    `json:"actor_id"`
+	ActorName     string `json:"actor_name"`
+	CharacterName string `json:"character_name"`
+	BillingOrder  *int   `json:"billing_order"`
+}
+
+// MovieGenresRequest replaces the genres of a movie.
+type MovieGenresRequest struct {
+	GenreIds []int `json:"genre_ids" binding:"required,dive,gt=0" example:"1,2"`
+}
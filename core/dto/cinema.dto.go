@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type CityRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CityResponse struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateCinemaRequest struct {
+	Name     string                `form:"name" binding:"required"`
+	Location string                `form:"location" binding:"required"`
+	CityId   int                   `form:"city_id" binding:"required"`
+	Logo     *multipart.FileHeader `form:"logo"`
+	LogoUrl  *string               `form:"-"`
+}
+
+type UpdateCinemaRequest struct {
+	Name     *string               `form:"name"`
+	Location *string               `form:"location"`
+	CityId   *int                  `form:"city_id"`
+	Logo     *multipart.FileHeader `form:"logo"`
+	LogoUrl  *string               `form:"-"`
+}
+
+type CinemaResponse struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	LogoUrl   string    `json:"logo_url"`
+	Location  string    `json:"location"`
+	CityId    int       `json:"city_id"`
+	CityName  string    `json:"city_name"`
+	Studios   int       `json:"studios"`
+	SeatCount int       `json:"seat_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditoriumRequest struct {
+	Name string `json:"name" binding:"required" example:"Studio 1"`
+}
+
+type AuditoriumResponse struct {
+	Id         int       `json:"id"`
+	CinemaId   int       `json:"cinema_id"`
+	CinemaName string    `json:"cinema_name"`
+	Name       string    `json:"name"`
+	SeatCount  int       `json:"seat_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SeatLayoutRequest replaces the seat grid of an auditorium. Seats of a row
+// are numbered 1 to Seats from the left; numbers listed in Gaps are left
+// empty, e.g. for an aisle, so the remaining seats keep their position.
+type SeatLayoutRequest struct {
+	Rows []SeatRowRequest `json:"rows" binding:"required,min=1,dive"`
+}
+
+type SeatRowRequest struct {
+	Row      string               `json:"row" binding:"required,alpha,max=2" example:"A"`
+	Seats    int                  `json:"seats" binding:"required,gt=0,lte=100" example:"14"`
+	SeatType string               `json:"seat_type" example:"regular"`
+	Gaps     []int                `json:"gaps" binding:"dive,gt=0" example:"5,10"`
+	Sections []SeatSectionRequest `json:"sections" binding:"dive"`
+}
+
+// SeatSectionRequest gives seats From to To of a row a different seat type
+// than the rest of the row.
+type SeatSectionRequest struct {
+	From     int    `json:"from" binding:"required,gt=0" example:"1"`
+	To       int    `json:"to" binding:"required,gt=0" example:"2"`
+	SeatType string `json:"seat_type" binding:"required" example:"love_nest"`
+}
+
+type SeatLayoutResponse struct {
+	AuditoriumId int             `json:"auditorium_id"`
+	CinemaId     int             `json:"cinema_id"`
+	TotalSeats   int             `json:"total_seats"`
+	Rows         []SeatLayoutRow `json:"rows"`
+}
+
+type SeatLayoutRow struct {
+	Row   string           `json:"row"`
+	Seats []SeatLayoutSeat `json:"seats"`
+}
+
+type SeatLayoutSeat struct {
+	SeatId     int    `json:"seat_id"`
+	SeatNumber int    `json:"seat_number"`
+	SeatType   string `json:"seat_type"`
+}
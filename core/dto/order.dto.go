@@ -12,21 +12,154 @@ type GetSchedules struct {
 	CinemaLogo     string    `json:"cinema_logo"`
 	CinemaLocation string    `json:"cinema_location"`
 	CinemaCity     string    `json:"cinema_city"`
+	AuditoriumId   int       `json:"auditorium_id"`
+	AuditoriumName string    `json:"auditorium_name"`
 }
 
 type CreateOrderRequest struct {
 	ScheduleId    int    `json:"schedule_id" binding:"required"`
 	Seats         []int  `json:"seats" binding:"required"`
 	PaymentMethod string `json:"payment_method" binding:"required"`
+	PromoCode     string `json:"promo_code"`
+	RedeemPoints  int    `json:"redeem_points" binding:"gte=0"`
 }
 
 type SeatResponse struct {
+	SeatId         int    `json:"seat_id"`
+	AuditoriumId   int    `json:"auditorium_id"`
+	AuditoriumName string `json:"auditorium_name"`
+	RowLetter      string `json:"row_letter"`
+	SeatNumber     int    `json:"seat_number"`
+	SeatType       string `json:"seat_type"`
+	Status         string `json:"status"`
+	Price          int    `json:"price"`
+}
+
+// SeatEvent is pushed to seat map streams whenever seats of a schedule
+// change status.
+type SeatEvent struct {
+	ScheduleId int    `json:"schedule_id"`
+	SeatIds    []int  `json:"seat_ids"`
+	Status     string `json:"status"`
+}
+
+type UpdateOrderRequest struct {
+	PaymentStatus string `json:"payment_status" binding:"required"`
+}
+
+type PaymentSession struct {
+	Provider      string `json:"provider"`
+	PaymentMethod string `json:"payment_method"`
+	Reference     string `json:"reference"`
+	PaymentUrl    string `json:"payment_url"`
+	Amount        int    `json:"amount"`
+	Status        string `json:"status"`
+}
+
+type OrderItem struct {
 	SeatId     int    `json:"seat_id"`
 	RowLetter  string `json:"row_letter"`
 	SeatNumber int    `json:"seat_number"`
 	SeatType   string `json:"seat_type"`
-	Status     string `json:"status"`
+	Price      int    `json:"price"`
 }
-type UpdateOrderRequest struct {
-	PaymentStatus string `json:"payment_status" binding:"required"`
+
+type CreateOrderResponse struct {
+	Id            int            `json:"id"`
+	BookingCode   string         `json:"booking_code"`
+	Items         []OrderItem    `json:"items"`
+	Subtotal      int            `json:"subtotal"`
+	Discount      int            `json:"discount"`
+	PromoCode     string         `json:"promo_code,omitempty"`
+	PointsUsed    int            `json:"points_used"`
+	PointsValue   int            `json:"points_value"`
+	TotalPrice    int            `json:"total_price"`
+	PaymentStatus string         `json:"payment_status"`
+	Payment       PaymentSession `json:"payment"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type SimulatePaymentRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type CancelOrderResponse struct {
+	Id            int    `json:"id"`
+	BookingCode   string `json:"booking_code"`
+	PaymentStatus string `json:"payment_status"`
+}
+
+type TicketMovie struct {
+	Id        int    `json:"id"`
+	Title     string `json:"title"`
+	PosterUrl string `json:"poster_url"`
+	Duration  int    `json:"duration"`
+}
+
+type TicketCinema struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	Logo       string `json:"logo"`
+	Location   string `json:"location"`
+	City       string `json:"city"`
+	Auditorium string `json:"auditorium"`
+}
+
+type TicketSeat struct {
+	SeatId     int    `json:"seat_id"`
+	RowLetter  string `json:"row_letter"`
+	SeatNumber int    `json:"seat_number"`
+	SeatType   string `json:"seat_type"`
+	Price      int    `json:"price"`
+}
+
+type PriceBreakdown struct {
+	Subtotal int `json:"subtotal"`
+	Discount int `json:"discount"`
+	Total    int `json:"total"`
+}
+
+type TicketPayment struct {
+	Method    string `json:"method"`
+	Provider  string `json:"provider"`
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Amount    int    `json:"amount"`
+}
+
+type OrderDetailResponse struct {
+	Id            int            `json:"id"`
+	BookingCode   string         `json:"booking_code"`
+	PaymentStatus string         `json:"payment_status"`
+	ScheduleId    int            `json:"schedule_id"`
+	ShowDate      time.Time      `json:"show_date"`
+	ShowTime      time.Time      `json:"show_time"`
+	Movie         TicketMovie    `json:"movie"`
+	Cinema        TicketCinema   `json:"cinema"`
+	Seats         []TicketSeat   `json:"seats"`
+	Price         PriceBreakdown `json:"price"`
+	Payment       *TicketPayment `json:"payment"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type CheckinRequest struct {
+	Code       string `json:"code" binding:"required"`
+	ScheduleId int    `json:"schedule_id" binding:"required"`
+	SeatIds    []int  `json:"seat_ids"`
+}
+
+type CheckinSeat struct {
+	SeatId     int        `json:"seat_id"`
+	RowLetter  string     `json:"row_letter"`
+	SeatNumber int        `json:"seat_number"`
+	SeatType   string     `json:"seat_type"`
+	AdmittedAt *time.Time `json:"admitted_at"`
+}
+
+type CheckinResponse struct {
+	OrderId     int           `json:"order_id"`
+	BookingCode string        `json:"booking_code"`
+	ScheduleId  int           `json:"schedule_id"`
+	Admitted    []int         `json:"admitted"`
+	Seats       []CheckinSeat `json:"seats"`
 }
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type PointTransactionResponse struct {
+	Id           int       `json:"id"`
+	OrderId      *int      `json:"order_id"`
+	PointsChange int       `json:"points_change"`
+	Description  string    `json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PointsResponse struct {
+	Balance      int                        `json:"balance"`
+	PointValue   int                        `json:"point_value"`
+	Transactions []PointTransactionResponse `json:"transactions"`
+}
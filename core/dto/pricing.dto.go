@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type SeatTypePriceRequest struct {
+	SeatType   string  `json:"seat_type" binding:"required"`
+	CinemaId   *int    `json:"cinema_id"`
+	ScheduleId *int    `json:"schedule_id"`
+	Multiplier float64 `json:"multiplier" binding:"required,gt=0"`
+	Surcharge  int     `json:"surcharge" binding:"gte=0"`
+}
+
+type SeatTypePriceResponse struct {
+	Id         int       `json:"id"`
+	SeatType   string    `json:"seat_type"`
+	CinemaId   *int      `json:"cinema_id"`
+	ScheduleId *int      `json:"schedule_id"`
+	Multiplier float64   `json:"multiplier"`
+	Surcharge  int       `json:"surcharge"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// CreateSchedulesRequest creates a show for every time slot on every day from
+// StartDate to EndDate.
+type CreateSchedulesRequest struct {
+	MovieId      int      `json:"movie_id" binding:"required"`
+	AuditoriumId int      `json:"auditorium_id" binding:"required"`
+	StartDate    string   `json:"start_date" binding:"required,datetime=2006-01-02" example:"2026-01-15"`
+	EndDate      string   `json:"end_date" binding:"required,datetime=2006-01-02" example:"2026-01-21"`
+	Times        []string `json:"times" binding:"required,min=1,dive,datetime=15:04" example:"13:00,16:30,20:00"`
+	Price        int      `json:"price" binding:"required,gt=0" example:"50000"`
+}
+
+type UpdateScheduleRequest struct {
+	MovieId      int    `json:"movie_id" binding:"required"`
+	AuditoriumId int    `json:"auditorium_id" binding:"required"`
+	ShowDate     string `json:"show_date" binding:"required,datetime=2006-01-02" example:"2026-01-15"`
+	ShowTime     string `json:"show_time" binding:"required,datetime=15:04" example:"13:00"`
+	Price        int    `json:"price" binding:"required,gt=0" example:"50000"`
+}
+
+type ScheduleResponse struct {
+	Id             int       `json:"id"`
+	MovieId        int       `json:"movie_id"`
+	MovieTitle     string    `json:"movie_title"`
+	CinemaId       int       `json:"cinema_id"`
+	CinemaName     string    `json:"cinema_name"`
+	AuditoriumId   int       `json:"auditorium_id"`
+	AuditoriumName string    `json:"auditorium_name"`
+	ShowDate       time.Time `json:"show_date"`
+	ShowTime       string    `json:"show_time"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	Price          int       `json:"price"`
+	PaidOrders     int       `json:"paid_orders"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -20,20 +20,49 @@ type User struct {
 }
 
 type NewUser struct {
-	Email    string `json:"email" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email     string `json:"email" binding:"required"`
+	Password  string `json:"password" binding:"required"`
+	UserAgent string `json:"-"`
+	IpAddress string `json:"-"`
 }
 
 type LoginResponse struct {
-	Id    int    `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
-	Token string `json:"token"`
+	Id           int    `json:"id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	IpAddress    string `json:"-"`
+}
+
+type ResendVerificationRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	IpAddress string `json:"-"`
+}
+
+type ForgotPasswordRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	IpAddress string `json:"-"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// LogoutRequest optionally carries the refresh token of the session, which is
+// revoked along with the access token.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 type RegisterResponse struct {
@@ -90,3 +119,18 @@ type UpdateProfileResponse struct {
 	PhoneNumber  string `json:"phone_number"`
 	ProfileImage string `json:"profile_image"`
 }
+
+type SessionResponse struct {
+	Id         int       `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `json:"current"`
+}
+
+// UnlockLoginRequest lifts the login lock of an email, an IP address or both.
+type UnlockLoginRequest struct {
+	Email     string `json:"email" binding:"omitempty,email" example:"user@mail.com"`
+	IpAddress string `json:"ip_address" binding:"omitempty,ip" example:"203.0.113.7"`
+}
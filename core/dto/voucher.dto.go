@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type VoucherRequest struct {
+	Code          string    `json:"code" binding:"required"`
+	Description   string    `json:"description"`
+	DiscountType  string    `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	DiscountValue int       `json:"discount_value" binding:"required,gt=0"`
+	MaxDiscount   *int      `json:"max_discount" binding:"omitempty,gt=0"`
+	MinPurchase   int       `json:"min_purchase" binding:"gte=0"`
+	StartsAt      time.Time `json:"starts_at" binding:"required"`
+	EndsAt        time.Time `json:"ends_at" binding:"required"`
+	UsageLimit    *int      `json:"usage_limit" binding:"omitempty,gt=0"`
+	PerUserLimit  *int      `json:"per_user_limit" binding:"omitempty,gt=0"`
+	MovieId       *int      `json:"movie_id"`
+	CinemaId      *int      `json:"cinema_id"`
+	CityId        *int      `json:"city_id"`
+	IsActive      *bool     `json:"is_active"`
+}
+
+type VoucherResponse struct {
+	Id            int       `json:"id"`
+	Code          string    `json:"code"`
+	Description   string    `json:"description"`
+	DiscountType  string    `json:"discount_type"`
+	DiscountValue int       `json:"discount_value"`
+	MaxDiscount   *int      `json:"max_discount"`
+	MinPurchase   int       `json:"min_purchase"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	UsageLimit    *int      `json:"usage_limit"`
+	PerUserLimit  *int      `json:"per_user_limit"`
+	MovieId       *int      `json:"movie_id"`
+	CinemaId      *int      `json:"cinema_id"`
+	CityId        *int      `json:"city_id"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
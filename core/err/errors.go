@@ -1,8 +1,174 @@
 package err
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrNoRowsUpdated = errors.New("no rows updated")
 	ErrInvalidExt    = errors.New("invalid file extension")
+
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrNoSeatsSelected  = errors.New("at least one seat must be selected")
+
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrOrderForbidden       = errors.New("order does not belong to the user")
+	ErrInvalidPaymentStatus = errors.New("invalid payment status")
+	ErrStatusNotAllowed     = errors.New("payment status can not be set by the user")
+	ErrCancellationClosed   = errors.New("order can no longer be cancelled")
+	ErrOrderNotPaid         = errors.New("order is not paid")
+	ErrTicketWrongSchedule  = errors.New("ticket is not valid for this schedule")
+
+	ErrInvalidPriceScope     = errors.New("exactly one of cinema_id or schedule_id is required")
+	ErrPriceScopeNotFound    = errors.New("cinema or schedule not found")
+	ErrSeatTypePriceNotFound = errors.New("seat type price not found")
+
+	ErrInsufficientPoints = errors.New("not enough loyalty points")
+
+	ErrVoucherNotFound    = errors.New("voucher not found")
+	ErrVoucherCodeTaken   = errors.New("voucher code already exists")
+	ErrVoucherInUse       = errors.New("voucher has been used, deactivate it instead")
+	ErrInvalidVoucherRule = errors.New("invalid voucher")
+
+	ErrCityNotFound      = errors.New("city not found")
+	ErrCityNameTaken     = errors.New("city already exists")
+	ErrCityInUse         = errors.New("city still has cinemas")
+	ErrCinemaNotFound    = errors.New("cinema not found")
+	ErrCinemaInUse       = errors.New("cinema has schedules")
+	ErrInvalidSeatLayout = errors.New("invalid seat layout")
+	ErrSeatsBooked       = errors.New("seats have orders and can not be removed")
+
+	ErrAuditoriumNotFound  = errors.New("auditorium not found")
+	ErrAuditoriumNameTaken = errors.New("cinema already has an auditorium with this name")
+	ErrAuditoriumInUse     = errors.New("auditorium has schedules")
+
+	ErrMovieNotFound         = errors.New("movie not found")
+	ErrInvalidSchedule       = errors.New("invalid schedule")
+	ErrScheduleHasPaidOrders = errors.New("schedule has paid orders")
+	// ErrScheduleHasPendingOrders is returned while orders on a schedule may
+	// still be paid; they are expired by the order expiry worker.
+	ErrScheduleHasPendingOrders = errors.New("schedule has pending orders")
+
+	ErrDirectorNotFound = errors.New("director not found")
+	ErrActorNotFound    = errors.New("actor not found")
+	ErrGenreNotFound    = errors.New("genre not found")
+	ErrGenreNameTaken   = errors.New("genre already exists")
+	ErrInvalidCast      = errors.New("invalid cast")
+
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, please login again")
+
+	ErrEmailNotVerified         = errors.New("email is not verified, check your inbox for the verification link")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	ErrSessionNotFound   = errors.New("session not found")
+
+	ErrInvalidUnlockRequest = errors.New("invalid unlock request")
+
+	ErrPaymentMethodNotFound     = errors.New("payment method not found")
+	ErrPaymentNotFound           = errors.New("payment not found")
+	ErrInvalidPaymentPayload     = errors.New("invalid payment payload")
+	ErrPaymentAmountMismatch     = errors.New("payment amount does not match the order")
+	ErrPaymentSimulationDisabled = errors.New("payment simulation is disabled")
 )
+
+type SeatTakenError struct {
+	SeatIds []int
+}
+
+func (e *SeatTakenError) Error() string {
+	return fmt.Sprintf("seat already taken: %v", e.SeatIds)
+}
+
+type InvalidSeatsError struct {
+	Reason  string
+	SeatIds []int
+}
+
+func (e *InvalidSeatsError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Reason, e.SeatIds)
+}
+
+type InvalidTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("can not change payment status from %s to %s", e.From, e.To)
+}
+
+// TicketUsedError is returned by check-in when the requested seats were
+// already admitted.
+type TicketUsedError struct {
+	SeatIds    []int
+	AdmittedAt time.Time
+}
+
+func (e *TicketUsedError) Error() string {
+	return fmt.Sprintf("ticket already used at %s: %v", e.AdmittedAt.Format(time.DateTime), e.SeatIds)
+}
+
+// VoucherError is returned when a promo code can not be applied to an order.
+type VoucherError struct {
+	Code   string
+	Reason string
+}
+
+func (e *VoucherError) Error() string {
+	return fmt.Sprintf("promo code %s can not be used: %s", e.Code, e.Reason)
+}
+
+// ScheduleOverlapError is returned when a show would overlap another show in
+// the same auditorium, cleaning buffer included. ScheduleId is 0 when the other
+// show is part of the same request.
+type ScheduleOverlapError struct {
+	StartsAt      time.Time
+	ScheduleId    int
+	Movie         string
+	OtherStartsAt time.Time
+}
+
+func (e *ScheduleOverlapError) Error() string {
+	if e.ScheduleId == 0 {
+		return fmt.Sprintf("show at %s overlaps the show at %s", e.StartsAt.Format("2006-01-02 15:04"), e.OtherStartsAt.Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("show at %s overlaps schedule %d (%s) at %s", e.StartsAt.Format("2006-01-02 15:04"), e.ScheduleId, e.Movie, e.OtherStartsAt.Format("2006-01-02 15:04"))
+}
+
+// LoginThrottledError is returned by login while an email or IP address is
+// backed off or locked out after failed attempts.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", e.Seconds())
+}
+
+// Seconds is RetryAfter rounded up, as sent in the Retry-After header.
+func (e *LoginThrottledError) Seconds() int {
+	return retryAfterSeconds(e.RetryAfter)
+}
+
+// RequestThrottledError is returned when an IP address made too many
+// requests that send mail, like password reset links.
+type RequestThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RequestThrottledError) Error() string {
+	return fmt.Sprintf("too many requests, try again in %d seconds", e.Seconds())
+}
+
+// Seconds is RetryAfter rounded up, as sent in the Retry-After header.
+func (e *RequestThrottledError) Seconds() int {
+	return retryAfterSeconds(e.RetryAfter)
+}
+
+func retryAfterSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// idempotencyLockTTL bounds how long a key stays reserved by a request that
+// never finishes, e.g. because the instance died while handling it.
+const idempotencyLockTTL = time.Minute
+
+type idempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// responseRecorder keeps a copy of everything the handler writes.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes a request with an Idempotency-Key header safe to retry.
+// The first response for a key is stored per user for IDEMPOTENCY_TTL and
+// replayed for every retry. Reusing a key for a different request, or while
+// the first one is still running, is rejected with 409. Server errors are
+// not stored, so they can be retried with the same key. It must run after
+// VerifyToken.
+func Idempotency(rdb *redis.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Data:    nil,
+				Error:   "Idempotency-Key is too long",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+				Msg:     "Bad Request",
+				Success: false,
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		fmt.Fprintf(hash, "%s %s\n", c.Request.Method, c.Request.URL.Path)
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := c.Request.Context()
+		rkey := fmt.Sprintf("bian:tickitz:idempotency:%d:%s", c.GetInt("user_id"), key)
+
+		lock, _ := json.Marshal(idempotencyRecord{RequestHash: requestHash})
+		reserved, err := rdb.SetNX(ctx, rkey, lock, idempotencyLockTTL).Result()
+		if err != nil {
+			log.Println("Idempotency Error:", err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+				Msg:     "Internal Server Error",
+				Success: false,
+				Data:    nil,
+				Error:   "internal server error",
+			})
+			return
+		}
+
+		if !reserved {
+			replayIdempotent(c, rdb, rkey, requestHash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The client may have hung up while the handler ran; the outcome
+		// still has to be stored or the key stays locked until it expires.
+		ctx = context.WithoutCancel(ctx)
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := rdb.Del(ctx, rkey).Err(); err != nil {
+				log.Println("Idempotency Error:", err.Error())
+			}
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			RequestHash: requestHash,
+			Done:        true,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		ttl := pkg.GetEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+		if err := rdb.Set(ctx, rkey, record, ttl).Err(); err != nil {
+			log.Println("Idempotency Error:", err.Error())
+		}
+	}
+}
+
+func replayIdempotent(c *gin.Context, rdb *redis.Client, rkey string, requestHash string) {
+	raw, err := rdb.Get(c.Request.Context(), rkey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// The first request failed and released the key in the meantime.
+			c.AbortWithStatusJSON(http.StatusConflict, dto.Response{
+				Msg:     "Conflict",
+				Success: false,
+				Data:    nil,
+				Error:   "request with this Idempotency-Key was just released, retry it",
+			})
+			return
+		}
+		log.Println("Idempotency Error:", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Data:    nil,
+			Error:   "internal server error",
+		})
+		return
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		log.Println("Idempotency Error:", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+			Msg:     "Internal Server Error",
+			Success: false,
+			Data:    nil,
+			Error:   "internal server error",
+		})
+		return
+	}
+
+	switch {
+	case record.RequestHash != requestHash:
+		c.AbortWithStatusJSON(http.StatusConflict, dto.Response{
+			Msg:     "Conflict",
+			Success: false,
+			Data:    nil,
+			Error:   "Idempotency-Key was already used for a different request",
+		})
+	case !record.Done:
+		c.AbortWithStatusJSON(http.StatusConflict, dto.Response{
+			Msg:     "Conflict",
+			Success: false,
+			Data:    nil,
+			Error:   "request with this Idempotency-Key is still being processed",
+		})
+	default:
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(record.Status, record.ContentType, record.Body)
+		c.Abort()
+	}
+}
@@ -2,13 +2,20 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
 	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 )
 
-func CheckRole(role string) gin.HandlerFunc {
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	RoleStaff = "staff"
+)
+
+func CheckRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, isExist := c.Get("token")
 		if !isExist {
@@ -32,7 +39,7 @@ func CheckRole(role string) gin.HandlerFunc {
 			return
 		}
 
-		if accessToken.Role != role {
+		if !slices.Contains(roles, accessToken.Role) {
 			c.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
 				Msg:     "Forbidden Access",
 				Success: false,
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// LimitConcurrentPerIP caps how many requests one client address may keep
+// open at the same time, for long lived endpoints such as event streams.
+// Counts are kept per instance.
+func LimitConcurrentPerIP(max int) gin.HandlerFunc {
+	var mu sync.Mutex
+	open := make(map[string]int)
+
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+
+		mu.Lock()
+		if open[ip] >= max {
+			mu.Unlock()
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.Response{
+				Msg:     "Too Many Requests",
+				Success: false,
+				Data:    []any{},
+				Error:   "too many open connections",
+			})
+			return
+		}
+		open[ip]++
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			open[ip]--
+			if open[ip] == 0 {
+				delete(open, ip)
+			}
+			mu.Unlock()
+		}()
+
+		c.Next()
+	}
+}
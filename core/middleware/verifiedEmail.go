@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// RequireVerifiedEmail only lets through tokens of users that verified their
+// email. Tokens without the claim, issued before it existed or before the
+// user verified, are checked against the database instead of rejected. It
+// must run after VerifyToken.
+func RequireVerifiedEmail(authRepository *repository.AuthRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, isExist := c.Get("token")
+		if !isExist {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
+				Msg:     "Forbidden Access",
+				Success: false,
+				Data:    []any{},
+				Error:   "Access Denied",
+			})
+			return
+		}
+
+		accessToken, ok := token.(pkg.JWTClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+				Msg:     "Internal Server Error",
+				Success: false,
+				Data:    []any{},
+				Error:   "internal server error",
+			})
+			return
+		}
+
+		if !accessToken.EmailVerified {
+			user, err := authRepository.FindUserById(c.Request.Context(), accessToken.Id)
+			if errors.Is(err, pgx.ErrNoRows) {
+				c.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
+					Msg:     "Forbidden Access",
+					Success: false,
+					Data:    []any{},
+					Error:   "Access Denied",
+				})
+				return
+			}
+			if err != nil {
+				log.Println("Error check email verification:", err.Error())
+				c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+					Msg:     "Internal Server Error",
+					Success: false,
+					Data:    []any{},
+					Error:   "internal server error",
+				})
+				return
+			}
+			if user.EmailVerifiedAt == nil {
+				c.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
+					Msg:     "Forbidden Access",
+					Success: false,
+					Data:    []any{},
+					Error:   "Email is not verified",
+				})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
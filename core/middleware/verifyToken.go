@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
 	"github.com/Albaihaqi354/Tickitz-BE/pkg"
@@ -72,8 +74,29 @@ func VerifyToken(rdb *redis.Client) gin.HandlerFunc {
 			})
 			return
 		}
+		if jc.SessionId != 0 {
+			trackSession(c, rdb, jc.SessionId)
+		}
+
 		c.Set("token", jc)
 		c.Set("user_id", jc.Id)
+		c.Set("session_id", jc.SessionId)
+		c.Set("role", jc.Role)
 		c.Next()
 	}
 }
+
+// trackSession records the time and IP of the latest request of a session,
+// shown by GET /user/sessions.
+func trackSession(c *gin.Context, rdb *redis.Client, sessionId int) {
+	ctx := c.Request.Context()
+	rkey := fmt.Sprintf("bian:tickitz:session:%d", sessionId)
+	_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, rkey, "last_seen_at", time.Now().Unix(), "ip_address", c.ClientIP())
+		pipe.Expire(ctx, rkey, pkg.RefreshTokenTTL())
+		return nil
+	})
+	if err != nil {
+		log.Println("Error track session:", err.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// RefreshToken is one link of a rotation chain. Every token issued by
+// rotation shares the FamilyId of the token issued at login; UsedAt is set
+// once the token has been exchanged.
+type RefreshToken struct {
+	Id        int        `db:"id"`
+	UserId    int        `db:"user_id"`
+	FamilyId  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// PasswordReset is a reset link sent by email. Only the hash of the token is
+// stored; UsedAt is set once it has been used or a newer link was sent.
+type PasswordReset struct {
+	Id        int        `db:"id"`
+	UserId    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// Session is one login of a user, from the login itself until it is logged
+// out, revoked or its refresh token family expires. The refresh tokens of the
+// login share its FamilyId.
+type Session struct {
+	Id         int        `db:"id"`
+	UserId     int        `db:"user_id"`
+	FamilyId   string     `db:"family_id"`
+	UserAgent  string     `db:"user_agent"`
+	IpAddress  string     `db:"ip_address"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	LastSeenAt time.Time  `db:"last_seen_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// SessionActivity is the latest request made with an access token of a
+// session, as tracked in Redis.
+type SessionActivity struct {
+	LastSeenAt time.Time
+	IpAddress  string
+}
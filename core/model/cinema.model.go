@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type City struct {
+	Id   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type Cinema struct {
+	Id        int       `db:"id"`
+	Name      string    `db:"name"`
+	LogoUrl   string    `db:"logo_url"`
+	Location  string    `db:"location"`
+	CityId    int       `db:"city_id"`
+	CityName  string    `db:"city_name"`
+	Studios   int       `db:"studios"`
+	SeatCount int       `db:"seat_count"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Auditorium is a studio (screen) of a cinema. Seats and schedules belong to
+// an auditorium.
+type Auditorium struct {
+	Id         int       `db:"id"`
+	CinemaId   int       `db:"cinema_id"`
+	CinemaName string    `db:"cinema_name"`
+	Name       string    `db:"name"`
+	SeatCount  int       `db:"seat_count"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -18,20 +18,23 @@ type Movie struct {
 }
 
 type Director struct {
-	Id        int       `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
+	Id         int       `db:"id"`
+	Name       string    `db:"name"`
+	MovieCount int       `db:"movie_count"`
+	CreatedAt  time.Time `db:"created_at"`
 }
 
 type Actor struct {
-	Id        int       `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
+	Id         int       `db:"id"`
+	Name       string    `db:"name"`
+	MovieCount int       `db:"movie_count"`
+	CreatedAt  time.Time `db:"created_at"`
 }
 
 type Genre struct {
-	Id   int    `db:"id"`
-	Name string `db:"name"`
+	Id         int    `db:"id"`
+	Name       string `db:"name"`
+	MovieCount int    `db:"movie_count"`
 }
 
 type MovieGenre struct {
@@ -39,9 +42,14 @@ type MovieGenre struct {
 	GenreId int `db:"genre_id"`
 }
 
+// MovieCast is an actor playing in a movie. CharacterName is empty and
+// BillingOrder nil when they are not known; billed actors are listed first.
 type MovieCast struct {
-	MovieId int `db:"movie_id"`
-	ActorId int `db:"actor_id"`
+	MovieId       int    `db:"movie_id"`
+	ActorId       int    `db:"actor_id"`
+	ActorName     string `db:"actor_name"`
+	CharacterName string `db:"character_name"`
+	BillingOrder  *int   `db:"billing_order"`
 }
 
 type MovieDetail struct {
@@ -2,6 +2,22 @@ package model
 
 import "time"
 
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusExpired   = "expired"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+	OrderStatusFailed    = "failed"
+)
+
+const (
+	OrderStatusSourceUser    = "user"
+	OrderStatusSourceAdmin   = "admin"
+	OrderStatusSourcePayment = "payment"
+	OrderStatusSourceSystem  = "system"
+)
+
 type GetSchedules struct {
 	Id             int       `db:"id"`
 	ShowDate       time.Time `db:"show_date"`
@@ -12,6 +28,8 @@ type GetSchedules struct {
 	CinemaLogo     string    `db:"cinema_logo"`
 	CinemaLocation string    `db:"cinema_location"`
 	CinemaCity     string    `db:"cinema_city"`
+	AuditoriumId   int       `db:"auditorium_id"`
+	AuditoriumName string    `db:"auditorium_name"`
 }
 
 type Order struct {
@@ -34,9 +52,61 @@ type OrderDetail struct {
 }
 
 type Seat struct {
-	SeatId     int    `db:"seat_id"`
-	RowLetter  string `db:"row_letter"`
-	SeatNumber int    `db:"seat_number"`
-	SeatType   string `db:"seat_type"`
-	Status     string `db:"status"`
+	SeatId         int    `db:"seat_id"`
+	CinemaId       int    `db:"cinema_id"`
+	AuditoriumId   int    `db:"auditorium_id"`
+	AuditoriumName string `db:"auditorium_name"`
+	RowLetter      string `db:"row_letter"`
+	SeatNumber     int    `db:"seat_number"`
+	SeatType       string `db:"seat_type"`
+	Status         string `db:"status"`
+	Price          int    `db:"price"`
+}
+
+type OrderStatusHistory struct {
+	Id         int       `db:"id"`
+	OrderId    int       `db:"order_id"`
+	FromStatus string    `db:"from_status"`
+	ToStatus   string    `db:"to_status"`
+	Source     string    `db:"source"`
+	ChangedBy  *int      `db:"changed_by"`
+	Note       string    `db:"note"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type OrderTicket struct {
+	Id               int       `db:"id"`
+	UserId           int       `db:"user_id"`
+	BookingCode      string    `db:"booking_code"`
+	TotalPrice       int       `db:"total_price"`
+	PaymentStatus    string    `db:"payment_status"`
+	CreatedAt        time.Time `db:"created_at"`
+	ScheduleId       int       `db:"schedule_id"`
+	ShowDate         time.Time `db:"show_date"`
+	ShowTime         time.Time `db:"show_time"`
+	Price            int       `db:"price"`
+	MovieId          int       `db:"movie_id"`
+	Title            string    `db:"title"`
+	PosterUrl        string    `db:"poster_url"`
+	Duration         int       `db:"duration"`
+	CinemaId         int       `db:"cinema_id"`
+	CinemaName       string    `db:"cinema_name"`
+	CinemaLogo       string    `db:"cinema_logo"`
+	CinemaLocation   string    `db:"cinema_location"`
+	CityName         string    `db:"city_name"`
+	AuditoriumName   string    `db:"auditorium_name"`
+	PaymentMethod    *string   `db:"payment_method"`
+	PaymentProvider  *string   `db:"payment_provider"`
+	PaymentReference *string   `db:"payment_reference"`
+	PaymentState     *string   `db:"payment_state"`
+	PaymentAmount    *int      `db:"payment_amount"`
+}
+
+type SeatAdmission struct {
+	SeatId     int        `db:"seat_id"`
+	RowLetter  string     `db:"row_letter"`
+	SeatNumber int        `db:"seat_number"`
+	SeatType   string     `db:"seat_type"`
+	AdmittedAt *time.Time `db:"admitted_at"`
+	AdmittedBy *int       `db:"admitted_by"`
 }
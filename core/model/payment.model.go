@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type PaymentMethod struct {
+	Id        int       `db:"id"`
+	Name      string    `db:"name"`
+	LogoUrl   string    `db:"logo_url"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type OrderPayment struct {
+	Id              int       `db:"id"`
+	OrderId         int       `db:"order_id"`
+	PaymentMethodId int       `db:"payment_method_id"`
+	Provider        string    `db:"provider"`
+	Reference       string    `db:"reference"`
+	Amount          int       `db:"amount"`
+	PaymentUrl      string    `db:"payment_url"`
+	Status          string    `db:"status"`
+	TransactionTime time.Time `db:"transaction_time"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type PointTransaction struct {
+	Id           int       `db:"id"`
+	UserId       int       `db:"user_id"`
+	OrderId      *int      `db:"order_id"`
+	PointsChange int       `db:"points_change"`
+	Description  string    `db:"description"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// SeatTypePrice adjusts the schedule price for one seat type, either for a
+// whole cinema or for a single schedule. Schedule rules win over cinema rules.
+type SeatTypePrice struct {
+	Id         int       `db:"id"`
+	SeatType   string    `db:"seat_type"`
+	CinemaId   *int      `db:"cinema_id"`
+	ScheduleId *int      `db:"schedule_id"`
+	Multiplier float64   `db:"multiplier"`
+	Surcharge  int       `db:"surcharge"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// Apply returns the price of a seat whose schedule price is basePrice.
+func (p SeatTypePrice) Apply(basePrice int) int {
+	return int(math.Round(float64(basePrice)*p.Multiplier)) + p.Surcharge
+}
@@ -0,0 +1,27 @@
+package model
+
+import "testing"
+
+func TestSeatTypePriceApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		price SeatTypePrice
+		base  int
+		want  int
+	}{
+		{"unchanged", SeatTypePrice{Multiplier: 1}, 50000, 50000},
+		{"multiplier", SeatTypePrice{Multiplier: 1.5}, 50000, 75000},
+		{"surcharge", SeatTypePrice{Multiplier: 1, Surcharge: 10000}, 50000, 60000},
+		{"multiplier then surcharge", SeatTypePrice{Multiplier: 2, Surcharge: 5000}, 40000, 85000},
+		{"discount", SeatTypePrice{Multiplier: 0.8}, 45000, 36000},
+		{"rounded", SeatTypePrice{Multiplier: 1.15}, 33333, 38333},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.Apply(tt.base); got != tt.want {
+				t.Errorf("Apply(%d) = %d, want %d", tt.base, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Schedule is a show of a movie in an auditorium of a cinema. StartsAt and
+// EndsAt are in the cinema's local time; EndsAt is StartsAt plus the movie
+// duration.
+type Schedule struct {
+	Id             int       `db:"id"`
+	MovieId        int       `db:"movie_id"`
+	MovieTitle     string    `db:"movie_title"`
+	CinemaId       int       `db:"cinema_id"`
+	CinemaName     string    `db:"cinema_name"`
+	AuditoriumId   int       `db:"auditorium_id"`
+	AuditoriumName string    `db:"auditorium_name"`
+	ShowDate       time.Time `db:"show_date"`
+	ShowTime       string    `db:"show_time"`
+	StartsAt       time.Time `db:"starts_at"`
+	EndsAt         time.Time `db:"ends_at"`
+	Price          int       `db:"price"`
+	PaidOrders     int       `db:"paid_orders"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// ScheduleOrderCounts counts the orders of a schedule that still hold seats.
+type ScheduleOrderCounts struct {
+	Paid    int
+	Pending int
+}
+
+type ScheduleFilter struct {
+	Ids          []int
+	MovieId      *int
+	CinemaId     *int
+	AuditoriumId *int
+	DateFrom     *string
+	DateTo       *string
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const (
+	VoucherTypePercentage = "percentage"
+	VoucherTypeFixed      = "fixed"
+)
+
+type Voucher struct {
+	Id            int       `db:"id"`
+	Code          string    `db:"code"`
+	Description   string    `db:"description"`
+	DiscountType  string    `db:"discount_type"`
+	DiscountValue int       `db:"discount_value"`
+	MaxDiscount   *int      `db:"max_discount"`
+	MinPurchase   int       `db:"min_purchase"`
+	StartsAt      time.Time `db:"starts_at"`
+	EndsAt        time.Time `db:"ends_at"`
+	UsageLimit    *int      `db:"usage_limit"`
+	PerUserLimit  *int      `db:"per_user_limit"`
+	MovieId       *int      `db:"movie_id"`
+	CinemaId      *int      `db:"cinema_id"`
+	CityId        *int      `db:"city_id"`
+	IsActive      bool      `db:"is_active"`
+	IsCurrent     bool      `db:"is_current"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// Discount returns the amount taken off subtotal, never more than subtotal.
+func (v Voucher) Discount(subtotal int) int {
+	discount := v.DiscountValue
+	if v.DiscountType == VoucherTypePercentage {
+		discount = subtotal * v.DiscountValue / 100
+		if v.MaxDiscount != nil {
+			discount = min(discount, *v.MaxDiscount)
+		}
+	}
+	return min(discount, subtotal)
+}
+
+type VoucherUsage struct {
+	Id        int       `db:"id"`
+	VoucherId int       `db:"voucher_id"`
+	UserId    int       `db:"user_id"`
+	OrderId   int       `db:"order_id"`
+	Discount  int       `db:"discount"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ScheduleScope is what a voucher restriction can be checked against.
+type ScheduleScope struct {
+	MovieId  int `db:"movie_id"`
+	CinemaId int `db:"cinema_id"`
+	CityId   int `db:"city_id"`
+}
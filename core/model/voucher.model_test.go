@@ -0,0 +1,31 @@
+package model
+
+import "testing"
+
+func TestVoucherDiscount(t *testing.T) {
+	maxDiscount := 20000
+
+	tests := []struct {
+		name     string
+		voucher  Voucher
+		subtotal int
+		want     int
+	}{
+		{"fixed", Voucher{DiscountType: VoucherTypeFixed, DiscountValue: 15000}, 100000, 15000},
+		{"fixed above subtotal", Voucher{DiscountType: VoucherTypeFixed, DiscountValue: 15000}, 10000, 10000},
+		{"percentage", Voucher{DiscountType: VoucherTypePercentage, DiscountValue: 10}, 100000, 10000},
+		{"percentage rounds down", Voucher{DiscountType: VoucherTypePercentage, DiscountValue: 15}, 33333, 4999},
+		{"percentage under cap", Voucher{DiscountType: VoucherTypePercentage, DiscountValue: 10, MaxDiscount: &maxDiscount}, 100000, 10000},
+		{"percentage capped", Voucher{DiscountType: VoucherTypePercentage, DiscountValue: 50, MaxDiscount: &maxDiscount}, 100000, 20000},
+		{"full percentage", Voucher{DiscountType: VoucherTypePercentage, DiscountValue: 100}, 45000, 45000},
+		{"empty order", Voucher{DiscountType: VoucherTypeFixed, DiscountValue: 15000}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.voucher.Discount(tt.subtotal); got != tt.want {
+				t.Errorf("Discount(%d) = %d, want %d", tt.subtotal, got, tt.want)
+			}
+		})
+	}
+}
@@ -28,7 +28,12 @@ func (a AdminRepository) GetAllMovieAdmin(ctx context.Context) ([]model.MovieDet
 			COALESCE(m.duration, 0) AS duration,
 			m.release_date,
 			COALESCE(d.name, '') AS director,
-			COALESCE(STRING_AGG(DISTINCT a.name, ', '), '') AS "cast",
+			COALESCE((
+				SELECT STRING_AGG(a.name, ', ' ORDER BY mc.billing_order NULLS LAST, a.name)
+				FROM movie_casts mc
+				INNER JOIN actors a ON mc.actor_id = a.id
+				WHERE mc.movie_id = m.id
+			), '') AS "cast",
 			COALESCE(m.poster_url, '') AS poster_url,
 			COALESCE(m.backdrop_url, '') AS backdrop_url,
 			COALESCE(m.popularity_score, 0) AS popularity_score,
@@ -38,8 +43,6 @@ func (a AdminRepository) GetAllMovieAdmin(ctx context.Context) ([]model.MovieDet
 			COUNT(DISTINCT s.id) AS schedule_count
 		FROM movies m
 		LEFT JOIN directors d ON m.director_id = d.id
-		LEFT JOIN movie_casts mc ON m.id = mc.movie_id
-		LEFT JOIN actors a ON mc.actor_id = a.id
 		LEFT JOIN movie_genres mg ON m.id = mg.movie_id
 		LEFT JOIN genres g ON mg.genre_id = g.id
 		LEFT JOIN schedules s ON s.movie_id = m.id
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
@@ -35,23 +37,131 @@ func (a AuthRepository) CreateNewUser(ctx context.Context, newUser dto.NewUser,
 }
 
 func (a AuthRepository) FindUserByEmail(ctx context.Context, email string) (model.User, error) {
-	sql := "SELECT id, email, password, role FROM users WHERE email = $1"
+	sql := "SELECT id, email, password, role, email_verified_at FROM users WHERE email = $1"
 
 	var user model.User
-	if err := a.db.QueryRow(ctx, sql, email).Scan(&user.Id, &user.Email, &user.Password, &user.Role); err != nil {
+	if err := a.db.QueryRow(ctx, sql, email).Scan(&user.Id, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt); err != nil {
 		return model.User{}, err
 	}
 	return user, nil
 }
 
-func (a AuthRepository) SaveToken(ctx context.Context, token string, ttl time.Duration) error {
+func (a AuthRepository) FindUserById(ctx context.Context, id int) (model.User, error) {
+	sql := "SELECT id, email, password, role, email_verified_at FROM users WHERE id = $1"
+
+	var user model.User
+	if err := a.db.QueryRow(ctx, sql, id).Scan(&user.Id, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// MarkEmailVerified verifies the email of the user if it is still the given
+// one. It reports whether the user was found with that email.
+func (a AuthRepository) MarkEmailVerified(ctx context.Context, id int, email string) (bool, error) {
+	sql := "UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE id = $1 AND email = $2"
+
+	tag, err := a.db.Exec(ctx, sql, id, email)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// userTokensKey and sessionTokensKey hold the access tokens whitelisted for
+// a user and for one of their sessions, so they can be revoked together.
+func userTokensKey(userId int) string {
+	return fmt.Sprintf("bian:tickitz:user_tokens:%d", userId)
+}
+
+func sessionTokensKey(sessionId int) string {
+	return fmt.Sprintf("bian:tickitz:session_tokens:%d", sessionId)
+}
+
+// sessionActivityKey is written by middleware.VerifyToken on every request.
+func sessionActivityKey(sessionId int) string {
+	return fmt.Sprintf("bian:tickitz:session:%d", sessionId)
+}
+
+func (a AuthRepository) SaveToken(ctx context.Context, userId int, sessionId int, token string, ttl time.Duration) error {
 	rkey := "bian:tickitz:whitelist:" + token
-	return a.redis.Set(ctx, rkey, "active", ttl).Err()
+	_, err := a.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, rkey, "active", ttl)
+		pipe.SAdd(ctx, userTokensKey(userId), token)
+		pipe.Expire(ctx, userTokensKey(userId), ttl)
+		pipe.SAdd(ctx, sessionTokensKey(sessionId), token)
+		pipe.Expire(ctx, sessionTokensKey(sessionId), ttl)
+		return nil
+	})
+	return err
 }
 
-func (a AuthRepository) DeleteToken(ctx context.Context, token string) error {
+func (a AuthRepository) DeleteToken(ctx context.Context, userId int, token string) error {
 	rkey := "bian:tickitz:whitelist:" + token
-	return a.redis.Del(ctx, rkey).Err()
+	_, err := a.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, rkey)
+		pipe.SRem(ctx, userTokensKey(userId), token)
+		return nil
+	})
+	return err
+}
+
+// RevokeSessionTokens removes every whitelisted access token of the session
+// along with its tracked activity.
+func (a AuthRepository) RevokeSessionTokens(ctx context.Context, sessionId int) error {
+	tokens, err := a.redis.SMembers(ctx, sessionTokensKey(sessionId)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{sessionTokensKey(sessionId), sessionActivityKey(sessionId)}
+	for _, token := range tokens {
+		keys = append(keys, "bian:tickitz:whitelist:"+token)
+	}
+	return a.redis.Del(ctx, keys...).Err()
+}
+
+// GetSessionActivity returns the tracked activity of the sessions that made
+// a request since their last refresh.
+func (a AuthRepository) GetSessionActivity(ctx context.Context, sessionIds []int) (map[int]model.SessionActivity, error) {
+	cmds := make([]*redis.MapStringStringCmd, len(sessionIds))
+	_, err := a.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range sessionIds {
+			cmds[i] = pipe.HGetAll(ctx, sessionActivityKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make(map[int]model.SessionActivity)
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		lastSeen, err := strconv.ParseInt(fields["last_seen_at"], 10, 64)
+		if err != nil {
+			continue
+		}
+		activity[sessionIds[i]] = model.SessionActivity{
+			LastSeenAt: time.Unix(lastSeen, 0).UTC(),
+			IpAddress:  fields["ip_address"],
+		}
+	}
+	return activity, nil
+}
+
+// RevokeUserTokens removes every whitelisted access token of the user.
+func (a AuthRepository) RevokeUserTokens(ctx context.Context, userId int) error {
+	tokens, err := a.redis.SMembers(ctx, userTokensKey(userId)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userTokensKey(userId)}
+	for _, token := range tokens {
+		keys = append(keys, "bian:tickitz:whitelist:"+token)
+	}
+	return a.redis.Del(ctx, keys...).Err()
 }
 
 func (a AuthRepository) TokenWhitelist(ctx context.Context, token string) (bool, error) {
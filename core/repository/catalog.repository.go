@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type CatalogRepo interface {
+	GetDirectors(ctx context.Context, db DBTX, search *string) ([]model.Director, error)
+	GetDirectorById(ctx context.Context, db DBTX, id int) (model.Director, error)
+	InsertDirector(ctx context.Context, db DBTX, name string) (int, error)
+	UpdateDirector(ctx context.Context, db DBTX, id int, name string) error
+	DeleteDirector(ctx context.Context, db DBTX, id int) error
+	GetActors(ctx context.Context, db DBTX, search *string) ([]model.Actor, error)
+	GetActorById(ctx context.Context, db DBTX, id int) (model.Actor, error)
+	InsertActor(ctx context.Context, db DBTX, name string) (int, error)
+	UpdateActor(ctx context.Context, db DBTX, id int, name string) error
+	DeleteActor(ctx context.Context, db DBTX, id int) error
+	GetGenres(ctx context.Context, db DBTX) ([]model.Genre, error)
+	GetGenreById(ctx context.Context, db DBTX, id int) (model.Genre, error)
+	InsertGenre(ctx context.Context, db DBTX, name string) (int, error)
+	UpdateGenre(ctx context.Context, db DBTX, id int, name string) error
+	DeleteGenre(ctx context.Context, db DBTX, id int) error
+	MovieExists(ctx context.Context, db DBTX, movieId int) (bool, error)
+	LockMovie(ctx context.Context, db DBTX, movieId int) error
+	GetMovieCast(ctx context.Context, db DBTX, movieId int) ([]model.MovieCast, error)
+	ReplaceMovieCast(ctx context.Context, db DBTX, movieId int, cast []model.MovieCast) error
+	GetMovieGenres(ctx context.Context, db DBTX, movieId int) ([]model.Genre, error)
+	ReplaceMovieGenres(ctx context.Context, db DBTX, movieId int, genreIds []int) error
+}
+
+type CatalogRepository struct{}
+
+func NewCatalogRepository() *CatalogRepository {
+	return &CatalogRepository{}
+}
+
+const directorSelect = `
+	SELECT d.id, d.name, (SELECT COUNT(*) FROM movies m WHERE m.director_id = d.id), d.created_at
+	FROM directors d`
+
+func scanDirector(row interface{ Scan(dest ...any) error }) (model.Director, error) {
+	var d model.Director
+	err := row.Scan(&d.Id, &d.Name, &d.MovieCount, &d.CreatedAt)
+	return d, err
+}
+
+func (r CatalogRepository) GetDirectors(ctx context.Context, db DBTX, search *string) ([]model.Director, error) {
+	sqlStr := directorSelect + `
+		WHERE ($1::text IS NULL OR d.name ILIKE '%' || $1 || '%')
+		ORDER BY d.name, d.id`
+
+	rows, err := db.Query(ctx, sqlStr, search)
+	if err != nil {
+		log.Println("GetDirectors Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var directors []model.Director
+	for rows.Next() {
+		director, err := scanDirector(rows)
+		if err != nil {
+			log.Println("GetDirectors Error:", err.Error())
+			return nil, err
+		}
+		directors = append(directors, director)
+	}
+	return directors, rows.Err()
+}
+
+func (r CatalogRepository) GetDirectorById(ctx context.Context, db DBTX, id int) (model.Director, error) {
+	director, err := scanDirector(db.QueryRow(ctx, directorSelect+" WHERE d.id = $1", id))
+	if err != nil {
+		log.Println("GetDirectorById Error:", err.Error())
+		return model.Director{}, err
+	}
+	return director, nil
+}
+
+func (r CatalogRepository) InsertDirector(ctx context.Context, db DBTX, name string) (int, error) {
+	var id int
+	err := db.QueryRow(ctx, "INSERT INTO directors (name) VALUES ($1) RETURNING id", name).Scan(&id)
+	if err != nil {
+		log.Println("InsertDirector Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r CatalogRepository) UpdateDirector(ctx context.Context, db DBTX, id int, name string) error {
+	tag, err := db.Exec(ctx, "UPDATE directors SET name = $2 WHERE id = $1", id, name)
+	if err != nil {
+		log.Println("UpdateDirector Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CatalogRepository) DeleteDirector(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM directors WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteDirector Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+const actorSelect = `
+	SELECT a.id, a.name, (SELECT COUNT(*) FROM movie_casts mc WHERE mc.actor_id = a.id), a.created_at
+	FROM actors a`
+
+func scanActor(row interface{ Scan(dest ...any) error }) (model.Actor, error) {
+	var a model.Actor
+	err := row.Scan(&a.Id, &a.Name, &a.MovieCount, &a.CreatedAt)
+	return a, err
+}
+
+func (r CatalogRepository) GetActors(ctx context.Context, db DBTX, search *string) ([]model.Actor, error) {
+	sqlStr := actorSelect + `
+		WHERE ($1::text IS NULL OR a.name ILIKE '%' || $1 || '%')
+		ORDER BY a.name, a.id`
+
+	rows, err := db.Query(ctx, sqlStr, search)
+	if err != nil {
+		log.Println("GetActors Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actors []model.Actor
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			log.Println("GetActors Error:", err.Error())
+			return nil, err
+		}
+		actors = append(actors, actor)
+	}
+	return actors, rows.Err()
+}
+
+func (r CatalogRepository) GetActorById(ctx context.Context, db DBTX, id int) (model.Actor, error) {
+	actor, err := scanActor(db.QueryRow(ctx, actorSelect+" WHERE a.id = $1", id))
+	if err != nil {
+		log.Println("GetActorById Error:", err.Error())
+		return model.Actor{}, err
+	}
+	return actor, nil
+}
+
+func (r CatalogRepository) InsertActor(ctx context.Context, db DBTX, name string) (int, error) {
+	var id int
+	err := db.QueryRow(ctx, "INSERT INTO actors (name) VALUES ($1) RETURNING id", name).Scan(&id)
+	if err != nil {
+		log.Println("InsertActor Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r CatalogRepository) UpdateActor(ctx context.Context, db DBTX, id int, name string) error {
+	tag, err := db.Exec(ctx, "UPDATE actors SET name = $2 WHERE id = $1", id, name)
+	if err != nil {
+		log.Println("UpdateActor Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CatalogRepository) DeleteActor(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM actors WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteActor Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+const genreSelect = `
+	SELECT g.id, g.name, (SELECT COUNT(*) FROM movie_genres mg WHERE mg.genre_id = g.id)
+	FROM genres g`
+
+func scanGenre(row interface{ Scan(dest ...any) error }) (model.Genre, error) {
+	var g model.Genre
+	err := row.Scan(&g.Id, &g.Name, &g.MovieCount)
+	return g, err
+}
+
+func (r CatalogRepository) GetGenres(ctx context.Context, db DBTX) ([]model.Genre, error) {
+	rows, err := db.Query(ctx, genreSelect+" ORDER BY g.name")
+	if err != nil {
+		log.Println("GetGenres Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var genres []model.Genre
+	for rows.Next() {
+		genre, err := scanGenre(rows)
+		if err != nil {
+			log.Println("GetGenres Error:", err.Error())
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, rows.Err()
+}
+
+func (r CatalogRepository) GetGenreById(ctx context.Context, db DBTX, id int) (model.Genre, error) {
+	genre, err := scanGenre(db.QueryRow(ctx, genreSelect+" WHERE g.id = $1", id))
+	if err != nil {
+		log.Println("GetGenreById Error:", err.Error())
+		return model.Genre{}, err
+	}
+	return genre, nil
+}
+
+func (r CatalogRepository) InsertGenre(ctx context.Context, db DBTX, name string) (int, error) {
+	var id int
+	err := db.QueryRow(ctx, "INSERT INTO genres (name) VALUES ($1) RETURNING id", name).Scan(&id)
+	if err != nil {
+		log.Println("InsertGenre Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r CatalogRepository) UpdateGenre(ctx context.Context, db DBTX, id int, name string) error {
+	tag, err := db.Exec(ctx, "UPDATE genres SET name = $2 WHERE id = $1", id, name)
+	if err != nil {
+		log.Println("UpdateGenre Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CatalogRepository) DeleteGenre(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM genres WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteGenre Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CatalogRepository) MovieExists(ctx context.Context, db DBTX, movieId int) (bool, error) {
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1)", movieId).Scan(&exists)
+	if err != nil {
+		log.Println("MovieExists Error:", err.Error())
+		return false, err
+	}
+	return exists, nil
+}
+
+// LockMovie locks the movie until the transaction ends, so concurrent edits of
+// its cast or genres do not interleave. Returns pgx.ErrNoRows when the movie
+// does not exist.
+func (r CatalogRepository) LockMovie(ctx context.Context, db DBTX, movieId int) error {
+	var id int
+	err := db.QueryRow(ctx, "SELECT id FROM movies WHERE id = $1 FOR UPDATE", movieId).Scan(&id)
+	if err != nil {
+		log.Println("LockMovie Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r CatalogRepository) GetMovieCast(ctx context.Context, db DBTX, movieId int) ([]model.MovieCast, error) {
+	sqlStr := `
+		SELECT mc.movie_id, mc.actor_id, a.name, COALESCE(mc.character_name, ''), mc.billing_order
+		FROM movie_casts mc
+		INNER JOIN actors a ON mc.actor_id = a.id
+		WHERE mc.movie_id = $1
+		ORDER BY mc.billing_order NULLS LAST, a.name`
+
+	rows, err := db.Query(ctx, sqlStr, movieId)
+	if err != nil {
+		log.Println("GetMovieCast Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cast []model.MovieCast
+	for rows.Next() {
+		var member model.MovieCast
+		if err := rows.Scan(&member.MovieId, &member.ActorId, &member.ActorName, &member.CharacterName, &member.BillingOrder); err != nil {
+			log.Println("GetMovieCast Error:", err.Error())
+			return nil, err
+		}
+		cast = append(cast, member)
+	}
+	return cast, rows.Err()
+}
+
+// ReplaceMovieCast deletes the cast of the movie and inserts the given one.
+// Empty character names are stored as NULL.
+func (r CatalogRepository) ReplaceMovieCast(ctx context.Context, db DBTX, movieId int, cast []model.MovieCast) error {
+	if _, err := db.Exec(ctx, "DELETE FROM movie_casts WHERE movie_id = $1", movieId); err != nil {
+		log.Println("ReplaceMovieCast Error:", err.Error())
+		return err
+	}
+	if len(cast) == 0 {
+		return nil
+	}
+
+	actorIds := make([]int, len(cast))
+	characters := make([]string, len(cast))
+	billing := make([]*int, len(cast))
+	for i, member := range cast {
+		actorIds[i] = member.ActorId
+		characters[i] = member.CharacterName
+		billing[i] = member.BillingOrder
+	}
+
+	sqlStr := `
+		INSERT INTO movie_casts (movie_id, actor_id, character_name, billing_order)
+		SELECT $1, t.actor_id, NULLIF(t.character_name, ''), t.billing_order
+		FROM UNNEST($2::int[], $3::text[], $4::int[]) AS t(actor_id, character_name, billing_order)`
+
+	if _, err := db.Exec(ctx, sqlStr, movieId, actorIds, characters, billing); err != nil {
+		log.Println("ReplaceMovieCast Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r CatalogRepository) GetMovieGenres(ctx context.Context, db DBTX, movieId int) ([]model.Genre, error) {
+	sqlStr := genreSelect + `
+		INNER JOIN movie_genres mg ON mg.genre_id = g.id
+		WHERE mg.movie_id = $1
+		ORDER BY g.name`
+
+	rows, err := db.Query(ctx, sqlStr, movieId)
+	if err != nil {
+		log.Println("GetMovieGenres Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var genres []model.Genre
+	for rows.Next() {
+		genre, err := scanGenre(rows)
+		if err != nil {
+			log.Println("GetMovieGenres Error:", err.Error())
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, rows.Err()
+}
+
+func (r CatalogRepository) ReplaceMovieGenres(ctx context.Context, db DBTX, movieId int, genreIds []int) error {
+	if _, err := db.Exec(ctx, "DELETE FROM movie_genres WHERE movie_id = $1", movieId); err != nil {
+		log.Println("ReplaceMovieGenres Error:", err.Error())
+		return err
+	}
+	if len(genreIds) == 0 {
+		return nil
+	}
+
+	sqlStr := `
+		INSERT INTO movie_genres (movie_id, genre_id)
+		SELECT $1, UNNEST($2::int[])`
+
+	if _, err := db.Exec(ctx, sqlStr, movieId, genreIds); err != nil {
+		log.Println("ReplaceMovieGenres Error:", err.Error())
+		return err
+	}
+	return nil
+}
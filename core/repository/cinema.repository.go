@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type CinemaRepo interface {
+	GetCities(ctx context.Context, db DBTX) ([]model.City, error)
+	GetCityForUpdate(ctx context.Context, db DBTX, id int) (model.City, error)
+	InsertCity(ctx context.Context, db DBTX, name string) (model.City, error)
+	UpdateCity(ctx context.Context, db DBTX, id int, name string) (model.City, error)
+	DeleteCity(ctx context.Context, db DBTX, id int) error
+	CountCinemasInCity(ctx context.Context, db DBTX, cityId int) (int, error)
+	GetCinemas(ctx context.Context, db DBTX, cityId *int) ([]model.Cinema, error)
+	GetCinemaById(ctx context.Context, db DBTX, id int) (model.Cinema, error)
+	LockCinema(ctx context.Context, db DBTX, id int) error
+	InsertCinema(ctx context.Context, db DBTX, req dto.CreateCinemaRequest) (int, error)
+	UpdateCinema(ctx context.Context, db DBTX, id int, req dto.UpdateCinemaRequest) error
+	DeleteCinema(ctx context.Context, db DBTX, id int) error
+	CountCinemaSchedules(ctx context.Context, db DBTX, cinemaId int) (int, error)
+	GetAuditoriums(ctx context.Context, db DBTX, cinemaId int) ([]model.Auditorium, error)
+	GetAuditoriumById(ctx context.Context, db DBTX, id int) (model.Auditorium, error)
+	GetAuditoriumForUpdate(ctx context.Context, db DBTX, id int) (model.Auditorium, error)
+	InsertAuditorium(ctx context.Context, db DBTX, cinemaId int, name string) (int, error)
+	UpdateAuditorium(ctx context.Context, db DBTX, id int, name string) error
+	DeleteAuditorium(ctx context.Context, db DBTX, id int) error
+	CountAuditoriumSchedules(ctx context.Context, db DBTX, auditoriumId int) (int, error)
+	GetAuditoriumSeats(ctx context.Context, db DBTX, auditoriumId int) ([]model.Seat, error)
+	GetOrderedSeatIds(ctx context.Context, db DBTX, seatIds []int) ([]int, error)
+	DeleteSeats(ctx context.Context, db DBTX, seatIds []int) error
+	UpsertSeats(ctx context.Context, db DBTX, auditorium model.Auditorium, seats []model.Seat) error
+}
+
+type CinemaRepository struct{}
+
+func NewCinemaRepository() *CinemaRepository {
+	return &CinemaRepository{}
+}
+
+func (r CinemaRepository) GetCities(ctx context.Context, db DBTX) ([]model.City, error) {
+	rows, err := db.Query(ctx, "SELECT id, name FROM cities ORDER BY name")
+	if err != nil {
+		log.Println("GetCities Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cities []model.City
+	for rows.Next() {
+		var city model.City
+		if err := rows.Scan(&city.Id, &city.Name); err != nil {
+			log.Println("GetCities Error:", err.Error())
+			return nil, err
+		}
+		cities = append(cities, city)
+	}
+	return cities, rows.Err()
+}
+
+// GetCityForUpdate locks the city until the transaction ends, so no cinema
+// can be added to it in the meantime.
+func (r CinemaRepository) GetCityForUpdate(ctx context.Context, db DBTX, id int) (model.City, error) {
+	var city model.City
+	err := db.QueryRow(ctx, "SELECT id, name FROM cities WHERE id = $1 FOR UPDATE", id).Scan(&city.Id, &city.Name)
+	if err != nil {
+		log.Println("GetCityForUpdate Error:", err.Error())
+		return model.City{}, err
+	}
+	return city, nil
+}
+
+func (r CinemaRepository) InsertCity(ctx context.Context, db DBTX, name string) (model.City, error) {
+	var city model.City
+	err := db.QueryRow(ctx, "INSERT INTO cities (name) VALUES ($1) RETURNING id, name", name).Scan(&city.Id, &city.Name)
+	if err != nil {
+		log.Println("InsertCity Error:", err.Error())
+		return model.City{}, err
+	}
+	return city, nil
+}
+
+func (r CinemaRepository) UpdateCity(ctx context.Context, db DBTX, id int, name string) (model.City, error) {
+	var city model.City
+	err := db.QueryRow(ctx, "UPDATE cities SET name = $2 WHERE id = $1 RETURNING id, name", id, name).Scan(&city.Id, &city.Name)
+	if err != nil {
+		log.Println("UpdateCity Error:", err.Error())
+		return model.City{}, err
+	}
+	return city, nil
+}
+
+func (r CinemaRepository) DeleteCity(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM cities WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteCity Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CinemaRepository) CountCinemasInCity(ctx context.Context, db DBTX, cityId int) (int, error) {
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM cinemas WHERE city_id = $1", cityId).Scan(&count)
+	if err != nil {
+		log.Println("CountCinemasInCity Error:", err.Error())
+		return 0, err
+	}
+	return count, nil
+}
+
+const cinemaSelect = `
+	SELECT
+		c.id,
+		c.name,
+		COALESCE(c.logo_url, ''),
+		c.location,
+		c.city_id,
+		ci.name,
+		(SELECT COUNT(*) FROM auditoriums a WHERE a.cinema_id = c.id),
+		(SELECT COUNT(*) FROM seats se WHERE se.cinema_id = c.id),
+		c.created_at
+	FROM cinemas c
+	INNER JOIN cities ci ON c.city_id = ci.id`
+
+func scanCinema(row interface{ Scan(dest ...any) error }) (model.Cinema, error) {
+	var c model.Cinema
+	err := row.Scan(
+		&c.Id,
+		&c.Name,
+		&c.LogoUrl,
+		&c.Location,
+		&c.CityId,
+		&c.CityName,
+		&c.Studios,
+		&c.SeatCount,
+		&c.CreatedAt,
+	)
+	return c, err
+}
+
+func (r CinemaRepository) GetCinemas(ctx context.Context, db DBTX, cityId *int) ([]model.Cinema, error) {
+	sqlStr := cinemaSelect + `
+		WHERE ($1::int IS NULL OR c.city_id = $1)
+		ORDER BY ci.name, c.name`
+
+	rows, err := db.Query(ctx, sqlStr, cityId)
+	if err != nil {
+		log.Println("GetCinemas Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cinemas []model.Cinema
+	for rows.Next() {
+		cinema, err := scanCinema(rows)
+		if err != nil {
+			log.Println("GetCinemas Error:", err.Error())
+			return nil, err
+		}
+		cinemas = append(cinemas, cinema)
+	}
+	return cinemas, rows.Err()
+}
+
+func (r CinemaRepository) GetCinemaById(ctx context.Context, db DBTX, id int) (model.Cinema, error) {
+	cinema, err := scanCinema(db.QueryRow(ctx, cinemaSelect+" WHERE c.id = $1", id))
+	if err != nil {
+		log.Println("GetCinemaById Error:", err.Error())
+		return model.Cinema{}, err
+	}
+	return cinema, nil
+}
+
+// LockCinema locks the cinema until the transaction ends. Returns
+// pgx.ErrNoRows when the cinema does not exist.
+func (r CinemaRepository) LockCinema(ctx context.Context, db DBTX, id int) error {
+	var cinemaId int
+	err := db.QueryRow(ctx, "SELECT id FROM cinemas WHERE id = $1 FOR UPDATE", id).Scan(&cinemaId)
+	if err != nil {
+		log.Println("LockCinema Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r CinemaRepository) InsertCinema(ctx context.Context, db DBTX, req dto.CreateCinemaRequest) (int, error) {
+	sqlStr := `
+		INSERT INTO cinemas (name, logo_url, location, city_id, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id`
+
+	var id int
+	err := db.QueryRow(ctx, sqlStr, req.Name, req.LogoUrl, req.Location, req.CityId).Scan(&id)
+	if err != nil {
+		log.Println("InsertCinema Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r CinemaRepository) UpdateCinema(ctx context.Context, db DBTX, id int, req dto.UpdateCinemaRequest) error {
+	sqlStr := `
+		UPDATE cinemas
+		SET
+			name = COALESCE($1, name),
+			location = COALESCE($2, location),
+			city_id = COALESCE($3, city_id),
+			logo_url = COALESCE($4, logo_url)
+		WHERE id = $5`
+
+	tag, err := db.Exec(ctx, sqlStr, req.Name, req.Location, req.CityId, req.LogoUrl, id)
+	if err != nil {
+		log.Println("UpdateCinema Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CinemaRepository) DeleteCinema(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM cinemas WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteCinema Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CinemaRepository) CountCinemaSchedules(ctx context.Context, db DBTX, cinemaId int) (int, error) {
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM schedules WHERE cinema_id = $1", cinemaId).Scan(&count)
+	if err != nil {
+		log.Println("CountCinemaSchedules Error:", err.Error())
+		return 0, err
+	}
+	return count, nil
+}
+
+const auditoriumSelect = `
+	SELECT
+		a.id,
+		a.cinema_id,
+		c.name,
+		a.name,
+		(SELECT COUNT(*) FROM seats se WHERE se.auditorium_id = a.id),
+		a.created_at
+	FROM auditoriums a
+	INNER JOIN cinemas c ON a.cinema_id = c.id`
+
+func scanAuditorium(row interface{ Scan(dest ...any) error }) (model.Auditorium, error) {
+	var a model.Auditorium
+	err := row.Scan(
+		&a.Id,
+		&a.CinemaId,
+		&a.CinemaName,
+		&a.Name,
+		&a.SeatCount,
+		&a.CreatedAt,
+	)
+	return a, err
+}
+
+func (r CinemaRepository) GetAuditoriums(ctx context.Context, db DBTX, cinemaId int) ([]model.Auditorium, error) {
+	rows, err := db.Query(ctx, auditoriumSelect+" WHERE a.cinema_id = $1 ORDER BY a.name", cinemaId)
+	if err != nil {
+		log.Println("GetAuditoriums Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditoriums []model.Auditorium
+	for rows.Next() {
+		auditorium, err := scanAuditorium(rows)
+		if err != nil {
+			log.Println("GetAuditoriums Error:", err.Error())
+			return nil, err
+		}
+		auditoriums = append(auditoriums, auditorium)
+	}
+	return auditoriums, rows.Err()
+}
+
+func (r CinemaRepository) GetAuditoriumById(ctx context.Context, db DBTX, id int) (model.Auditorium, error) {
+	auditorium, err := scanAuditorium(db.QueryRow(ctx, auditoriumSelect+" WHERE a.id = $1", id))
+	if err != nil {
+		log.Println("GetAuditoriumById Error:", err.Error())
+		return model.Auditorium{}, err
+	}
+	return auditorium, nil
+}
+
+// GetAuditoriumForUpdate locks the auditorium until the transaction ends.
+// Seat layout and schedule changes of an auditorium are serialized on it.
+func (r CinemaRepository) GetAuditoriumForUpdate(ctx context.Context, db DBTX, id int) (model.Auditorium, error) {
+	auditorium, err := scanAuditorium(db.QueryRow(ctx, auditoriumSelect+" WHERE a.id = $1 FOR UPDATE OF a", id))
+	if err != nil {
+		log.Println("GetAuditoriumForUpdate Error:", err.Error())
+		return model.Auditorium{}, err
+	}
+	return auditorium, nil
+}
+
+func (r CinemaRepository) InsertAuditorium(ctx context.Context, db DBTX, cinemaId int, name string) (int, error) {
+	var id int
+	err := db.QueryRow(ctx, "INSERT INTO auditoriums (cinema_id, name) VALUES ($1, $2) RETURNING id", cinemaId, name).Scan(&id)
+	if err != nil {
+		log.Println("InsertAuditorium Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r CinemaRepository) UpdateAuditorium(ctx context.Context, db DBTX, id int, name string) error {
+	tag, err := db.Exec(ctx, "UPDATE auditoriums SET name = $2 WHERE id = $1", id, name)
+	if err != nil {
+		log.Println("UpdateAuditorium Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CinemaRepository) DeleteAuditorium(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM auditoriums WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteAuditorium Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r CinemaRepository) CountAuditoriumSchedules(ctx context.Context, db DBTX, auditoriumId int) (int, error) {
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM schedules WHERE auditorium_id = $1", auditoriumId).Scan(&count)
+	if err != nil {
+		log.Println("CountAuditoriumSchedules Error:", err.Error())
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r CinemaRepository) GetAuditoriumSeats(ctx context.Context, db DBTX, auditoriumId int) ([]model.Seat, error) {
+	sqlStr := `
+		SELECT id, cinema_id, auditorium_id, row_letter, seat_number, COALESCE(seat_type, 'regular')
+		FROM seats
+		WHERE auditorium_id = $1
+		ORDER BY LENGTH(row_letter), row_letter, seat_number`
+
+	rows, err := db.Query(ctx, sqlStr, auditoriumId)
+	if err != nil {
+		log.Println("GetAuditoriumSeats Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []model.Seat
+	for rows.Next() {
+		var s model.Seat
+		if err := rows.Scan(&s.SeatId, &s.CinemaId, &s.AuditoriumId, &s.RowLetter, &s.SeatNumber, &s.SeatType); err != nil {
+			log.Println("GetAuditoriumSeats Error:", err.Error())
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, rows.Err()
+}
+
+// GetOrderedSeatIds returns the seats among seatIds that appear in any order,
+// whatever its status. Removing them would delete the order history.
+func (r CinemaRepository) GetOrderedSeatIds(ctx context.Context, db DBTX, seatIds []int) ([]int, error) {
+	sqlStr := "SELECT DISTINCT seat_id FROM order_details WHERE seat_id = ANY($1) ORDER BY seat_id"
+
+	rows, err := db.Query(ctx, sqlStr, seatIds)
+	if err != nil {
+		log.Println("GetOrderedSeatIds Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ordered []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Println("GetOrderedSeatIds Error:", err.Error())
+			return nil, err
+		}
+		ordered = append(ordered, id)
+	}
+	return ordered, rows.Err()
+}
+
+func (r CinemaRepository) DeleteSeats(ctx context.Context, db DBTX, seatIds []int) error {
+	_, err := db.Exec(ctx, "DELETE FROM seats WHERE id = ANY($1)", seatIds)
+	if err != nil {
+		log.Println("DeleteSeats Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+// UpsertSeats inserts the seats of the auditorium, updating the seat type of
+// the ones that already exist at the same row and number.
+func (r CinemaRepository) UpsertSeats(ctx context.Context, db DBTX, auditorium model.Auditorium, seats []model.Seat) error {
+	rowLetters := make([]string, len(seats))
+	seatNumbers := make([]int, len(seats))
+	seatTypes := make([]string, len(seats))
+	for i, s := range seats {
+		rowLetters[i] = s.RowLetter
+		seatNumbers[i] = s.SeatNumber
+		seatTypes[i] = s.SeatType
+	}
+
+	sqlStr := `
+		INSERT INTO seats (cinema_id, auditorium_id, row_letter, seat_number, seat_type)
+		SELECT $1, $2, s.row_letter, s.seat_number, s.seat_type
+		FROM UNNEST($3::varchar[], $4::int[], $5::varchar[]) AS s(row_letter, seat_number, seat_type)
+		ON CONFLICT (auditorium_id, row_letter, seat_number)
+		DO UPDATE SET seat_type = EXCLUDED.seat_type`
+
+	_, err := db.Exec(ctx, sqlStr, auditorium.CinemaId, auditorium.Id, rowLetters, seatNumbers, seatTypes)
+	if err != nil {
+		log.Println("UpsertSeats Error:", err.Error())
+		return err
+	}
+	return nil
+}
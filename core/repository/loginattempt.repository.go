@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginAttemptRepository counts failed logins and holds login locks. Both are
+// kept per scope, "email" or "ip", so a guesser is slowed down whether they
+// try many passwords on one account or one password on many accounts.
+type LoginAttemptRepository struct {
+	redis *redis.Client
+}
+
+func NewLoginAttemptRepository(rdb *redis.Client) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		redis: rdb,
+	}
+}
+
+func loginAttemptsKey(scope string, id string) string {
+	return "bian:tickitz:login_attempts:" + scope + ":" + id
+}
+
+func loginLockKey(scope string, id string) string {
+	return "bian:tickitz:login_lock:" + scope + ":" + id
+}
+
+// GetLock returns how long logins stay locked, or 0 when they are not.
+func (r LoginAttemptRepository) GetLock(ctx context.Context, scope string, id string) (time.Duration, error) {
+	ttl, err := r.redis.PTTL(ctx, loginLockKey(scope, id)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL is negative for missing keys and keys without expiry.
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RecordFailure counts a failed login and returns the number of failures.
+// The count is forgotten once no login has failed for window.
+func (r LoginAttemptRepository) RecordFailure(ctx context.Context, scope string, id string, window time.Duration) (int, error) {
+	key := loginAttemptsKey(scope, id)
+	var incr *redis.IntCmd
+	_, err := r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.PExpire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (r LoginAttemptRepository) Lock(ctx context.Context, scope string, id string, d time.Duration) error {
+	return r.redis.Set(ctx, loginLockKey(scope, id), "locked", d).Err()
+}
+
+// Clear forgets the failures and lifts the lock.
+func (r LoginAttemptRepository) Clear(ctx context.Context, scope string, id string) error {
+	return r.redis.Del(ctx, loginAttemptsKey(scope, id), loginLockKey(scope, id)).Err()
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MailThrottleRepository keeps the cooldowns and request counts that limit
+// how often account mail, like password reset links, can be requested.
+type MailThrottleRepository struct {
+	redis *redis.Client
+}
+
+func NewMailThrottleRepository(rdb *redis.Client) *MailThrottleRepository {
+	return &MailThrottleRepository{
+		redis: rdb,
+	}
+}
+
+func mailCooldownKey(kind string, email string) string {
+	return "bian:tickitz:mail_cooldown:" + kind + ":" + email
+}
+
+func mailRequestsKey(kind string, ipAddress string) string {
+	return "bian:tickitz:mail_requests:" + kind + ":" + ipAddress
+}
+
+// StartCooldown starts a cooldown of d for the email and reports whether
+// none was running yet.
+func (r MailThrottleRepository) StartCooldown(ctx context.Context, kind string, email string, d time.Duration) (bool, error) {
+	return r.redis.SetNX(ctx, mailCooldownKey(kind, email), "sent", d).Result()
+}
+
+// RecordRequest counts a request from the IP address in a fixed window and
+// returns the count and how long the window still runs.
+func (r MailThrottleRepository) RecordRequest(ctx context.Context, kind string, ipAddress string, window time.Duration) (int, time.Duration, error) {
+	key := mailRequestsKey(kind, ipAddress)
+	count, err := r.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	ttl, err := r.redis.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	// A new key has no expiry yet; PTTL is negative for it.
+	if ttl < 0 {
+		if err := r.redis.PExpire(ctx, key, window).Err(); err != nil {
+			return 0, 0, err
+		}
+		ttl = window
+	}
+	return int(count), ttl, nil
+}
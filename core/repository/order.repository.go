@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
 	"github.com/Albaihaqi354/Tickitz-BE/core/model"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -19,10 +20,23 @@ type DBTX interface {
 type OrderRepo interface {
 	GetSchedules(ctx context.Context, db DBTX, movieId int, showDate *string, city *string) ([]model.GetSchedules, error)
 	InsertOrder(ctx context.Context, db DBTX, order model.Order) (int, string, time.Time, error)
-	InsertOrderDetail(ctx context.Context, db DBTX, orderId int, seatId int) error
+	InsertOrderDetails(ctx context.Context, db DBTX, orderId int, seatIds []int, prices []int) ([]int, error)
 	GetSeatsByScheduleID(ctx context.Context, db DBTX, scheduleId int) ([]model.Seat, error)
 	GetPriceFromSchedule(ctx context.Context, db DBTX, scheduleId int) (int, error)
-	UpdatePaymentStatus(ctx context.Context, db DBTX, orderId int, status string) error
+	GetAuditoriumIdFromSchedule(ctx context.Context, db DBTX, scheduleId int) (int, error)
+	GetSeatsByIds(ctx context.Context, db DBTX, seatIds []int) ([]model.Seat, error)
+	UpdatePaymentStatus(ctx context.Context, db DBTX, orderId int, fromStatus string, toStatus string) error
+	GetOrderById(ctx context.Context, db DBTX, orderId int) (model.Order, error)
+	GetOrderByIdForUpdate(ctx context.Context, db DBTX, orderId int) (model.Order, error)
+	InsertOrderStatusHistory(ctx context.Context, db DBTX, history model.OrderStatusHistory) error
+	GetSeatIdsByOrderId(ctx context.Context, db DBTX, orderId int) ([]int, error)
+	GetStalePendingOrderIds(ctx context.Context, db DBTX, ttl time.Duration, limit int) ([]int, error)
+	GetTimeUntilShow(ctx context.Context, db DBTX, scheduleId int) (time.Duration, error)
+	GetOrderTicket(ctx context.Context, db DBTX, orderId int) (model.OrderTicket, error)
+	GetSeatsByOrderId(ctx context.Context, db DBTX, orderId int) ([]model.Seat, error)
+	GetOrderIdByBookingCode(ctx context.Context, db DBTX, bookingCode string) (int, error)
+	GetSeatAdmissions(ctx context.Context, db DBTX, orderId int) ([]model.SeatAdmission, error)
+	AdmitSeats(ctx context.Context, db DBTX, orderId int, seatIds []int, admittedBy int) ([]int, error)
 }
 
 type OrderRepository struct{}
@@ -42,10 +56,13 @@ func (o OrderRepository) GetSchedules(ctx context.Context, db DBTX, movieId int,
 			c.name AS cinema_name,
 			c.logo_url AS cinema_logo,
 			c.location AS cinema_location,
-			ci.name AS cinema_city
+			ci.name AS cinema_city,
+			a.id AS auditorium_id,
+			a.name AS auditorium_name
 		FROM schedules s
 		INNER JOIN cinemas c ON s.cinema_id = c.id
 		INNER JOIN cities ci ON c.city_id = ci.id
+		INNER JOIN auditoriums a ON s.auditorium_id = a.id
 		WHERE s.movie_id = $1
 			AND ($2::DATE IS NULL OR s.show_date = $2)
 			AND ($3::VARCHAR IS NULL OR ci.name = $3)
@@ -72,6 +89,8 @@ func (o OrderRepository) GetSchedules(ctx context.Context, db DBTX, movieId int,
 			&s.CinemaLogo,
 			&s.CinemaLocation,
 			&s.CinemaCity,
+			&s.AuditoriumId,
+			&s.AuditoriumName,
 		)
 		if err != nil {
 			log.Println("Scan error:", err.Error())
@@ -101,20 +120,46 @@ func (o OrderRepository) InsertOrder(ctx context.Context, db DBTX, order model.O
 	return id, bookingCode, createdAt, nil
 }
 
-func (o OrderRepository) InsertOrderDetail(ctx context.Context, db DBTX, orderId int, seatId int) error {
-	sqlOrderDetail := `INSERT INTO order_details (order_id, seat_id) VALUES ($1, $2)`
-	_, err := db.Exec(ctx, sqlOrderDetail, orderId, seatId)
+// InsertOrderDetails books the seats for an order and returns the seats that
+// were inserted. Seats already booked by another active order on the same
+// schedule are skipped by the order_details_active_seat_key index.
+func (o OrderRepository) InsertOrderDetails(ctx context.Context, db DBTX, orderId int, seatIds []int, prices []int) ([]int, error) {
+	sqlOrderDetail := `
+		INSERT INTO order_details (order_id, seat_id, price)
+		SELECT $1, t.seat_id, t.price
+		FROM unnest($2::int[], $3::numeric[]) AS t(seat_id, price)
+		ON CONFLICT (schedule_id, seat_id) WHERE is_active DO NOTHING
+		RETURNING seat_id`
+
+	rows, err := db.Query(ctx, sqlOrderDetail, orderId, seatIds, prices)
 	if err != nil {
-		log.Println("InsertOrderDetail Error:", err.Error())
-		return err
+		log.Println("InsertOrderDetails Error:", err.Error())
+		return nil, err
 	}
-	return nil
+	defer rows.Close()
+
+	var inserted []int
+	for rows.Next() {
+		var seatId int
+		if err := rows.Scan(&seatId); err != nil {
+			log.Println("InsertOrderDetails Error:", err.Error())
+			return nil, err
+		}
+		inserted = append(inserted, seatId)
+	}
+	if err := rows.Err(); err != nil {
+		log.Println("InsertOrderDetails Error:", err.Error())
+		return nil, err
+	}
+	return inserted, nil
 }
 
 func (o OrderRepository) GetSeatsByScheduleID(ctx context.Context, db DBTX, scheduleId int) ([]model.Seat, error) {
 	sqlStr := `
 		SELECT 
 			se.id AS seat_id,
+			a.id AS auditorium_id,
+			a.name AS auditorium_name,
 			se.row_letter,
 			se.seat_number,
 			se.seat_type,
@@ -127,11 +172,18 @@ func (o OrderRepository) GetSeatsByScheduleID(ctx context.Context, db DBTX, sche
 						AND o.payment_status = 'paid' 
 						AND od.seat_id = se.id
 				) THEN 'sold'
+				WHEN EXISTS (
+					SELECT 1
+					FROM order_details od
+					WHERE od.schedule_id = sch.id
+						AND od.seat_id = se.id
+						AND od.is_active
+				) THEN 'held'
 				ELSE 'available'
 			END AS status
 		FROM schedules sch
-		INNER JOIN cinemas c ON sch.cinema_id = c.id
-		INNER JOIN seats se ON se.cinema_id = c.id
+		INNER JOIN auditoriums a ON sch.auditorium_id = a.id
+		INNER JOIN seats se ON se.auditorium_id = a.id
 		WHERE sch.id = $1
 		ORDER BY se.row_letter, se.seat_number;
 	`
@@ -145,7 +197,7 @@ func (o OrderRepository) GetSeatsByScheduleID(ctx context.Context, db DBTX, sche
 	var seats []model.Seat
 	for rows.Next() {
 		var s model.Seat
-		err := rows.Scan(&s.SeatId, &s.RowLetter, &s.SeatNumber, &s.SeatType, &s.Status)
+		err := rows.Scan(&s.SeatId, &s.AuditoriumId, &s.AuditoriumName, &s.RowLetter, &s.SeatNumber, &s.SeatType, &s.Status)
 		if err != nil {
 			return nil, err
 		}
@@ -161,12 +213,339 @@ func (o OrderRepository) GetPriceFromSchedule(ctx context.Context, db DBTX, sche
 	return price, err
 }
 
-func (o OrderRepository) UpdatePaymentStatus(ctx context.Context, db DBTX, orderId int, status string) error {
-	sqlStr := "UPDATE orders SET payment_status = $1 WHERE id = $2"
-	_, err := db.Exec(ctx, sqlStr, status, orderId)
+func (o OrderRepository) GetAuditoriumIdFromSchedule(ctx context.Context, db DBTX, scheduleId int) (int, error) {
+	sqlStr := "SELECT auditorium_id FROM schedules WHERE id = $1"
+	var auditoriumId int
+	err := db.QueryRow(ctx, sqlStr, scheduleId).Scan(&auditoriumId)
+	return auditoriumId, err
+}
+
+func (o OrderRepository) GetSeatsByIds(ctx context.Context, db DBTX, seatIds []int) ([]model.Seat, error) {
+	sqlStr := `
+		SELECT id, cinema_id, auditorium_id, row_letter, seat_number, COALESCE(seat_type, 'regular')
+		FROM seats
+		WHERE id = ANY($1::int[])`
+
+	rows, err := db.Query(ctx, sqlStr, seatIds)
+	if err != nil {
+		log.Println("GetSeatsByIds Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []model.Seat
+	for rows.Next() {
+		var s model.Seat
+		if err := rows.Scan(&s.SeatId, &s.CinemaId, &s.AuditoriumId, &s.RowLetter, &s.SeatNumber, &s.SeatType); err != nil {
+			log.Println("GetSeatsByIds Error:", err.Error())
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, rows.Err()
+}
+
+func (o OrderRepository) UpdatePaymentStatus(ctx context.Context, db DBTX, orderId int, fromStatus string, toStatus string) error {
+	sqlStr := "UPDATE orders SET payment_status = $1 WHERE id = $2 AND payment_status = $3"
+	tag, err := db.Exec(ctx, sqlStr, toStatus, orderId, fromStatus)
 	if err != nil {
 		log.Println("UpdatePaymentStatus Error:", err.Error())
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
 	return nil
 }
+
+func (o OrderRepository) GetOrderById(ctx context.Context, db DBTX, orderId int) (model.Order, error) {
+	return o.getOrder(ctx, db, orderId, "")
+}
+
+// GetOrderByIdForUpdate locks the order row until the surrounding
+// transaction ends, so concurrent status changes are applied one by one.
+func (o OrderRepository) GetOrderByIdForUpdate(ctx context.Context, db DBTX, orderId int) (model.Order, error) {
+	return o.getOrder(ctx, db, orderId, "FOR UPDATE")
+}
+
+func (o OrderRepository) getOrder(ctx context.Context, db DBTX, orderId int, lock string) (model.Order, error) {
+	sqlStr := `
+		SELECT id, user_id, schedule_id, booking_code, total_price, COALESCE(payment_status, 'pending'), created_at
+		FROM orders
+		WHERE id = $1 ` + lock
+
+	var order model.Order
+	err := db.QueryRow(ctx, sqlStr, orderId).Scan(
+		&order.Id,
+		&order.UserId,
+		&order.ScheduleId,
+		&order.BookingCode,
+		&order.TotalPrice,
+		&order.PaymentStatus,
+		&order.CreatedAt,
+	)
+	if err != nil {
+		log.Println("GetOrderById Error:", err.Error())
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+func (o OrderRepository) GetSeatIdsByOrderId(ctx context.Context, db DBTX, orderId int) ([]int, error) {
+	sqlStr := "SELECT seat_id FROM order_details WHERE order_id = $1 ORDER BY seat_id"
+
+	rows, err := db.Query(ctx, sqlStr, orderId)
+	if err != nil {
+		log.Println("GetSeatIdsByOrderId Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIds []int
+	for rows.Next() {
+		var seatId int
+		if err := rows.Scan(&seatId); err != nil {
+			return nil, err
+		}
+		seatIds = append(seatIds, seatId)
+	}
+	return seatIds, rows.Err()
+}
+
+func (o OrderRepository) InsertOrderStatusHistory(ctx context.Context, db DBTX, history model.OrderStatusHistory) error {
+	sqlStr := `
+		INSERT INTO order_status_histories (order_id, from_status, to_status, source, changed_by, note)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := db.Exec(ctx, sqlStr,
+		history.OrderId,
+		history.FromStatus,
+		history.ToStatus,
+		history.Source,
+		history.ChangedBy,
+		history.Note,
+	)
+	if err != nil {
+		log.Println("InsertOrderStatusHistory Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (o OrderRepository) GetStalePendingOrderIds(ctx context.Context, db DBTX, ttl time.Duration, limit int) ([]int, error) {
+	sqlStr := `
+		SELECT id
+		FROM orders
+		WHERE payment_status = 'pending'
+			AND created_at < NOW() - make_interval(secs => $1)
+		ORDER BY created_at
+		LIMIT $2`
+
+	rows, err := db.Query(ctx, sqlStr, ttl.Seconds(), limit)
+	if err != nil {
+		log.Println("GetStalePendingOrderIds Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orderIds []int
+	for rows.Next() {
+		var orderId int
+		if err := rows.Scan(&orderId); err != nil {
+			return nil, err
+		}
+		orderIds = append(orderIds, orderId)
+	}
+	return orderIds, rows.Err()
+}
+
+// GetTimeUntilShow is computed by the database because show_date and
+// show_time are stored in the cinema's local time, like created_at.
+func (o OrderRepository) GetTimeUntilShow(ctx context.Context, db DBTX, scheduleId int) (time.Duration, error) {
+	sqlStr := `
+		SELECT EXTRACT(EPOCH FROM (show_date + show_time) - LOCALTIMESTAMP)::float8
+		FROM schedules
+		WHERE id = $1`
+
+	var seconds float64
+	err := db.QueryRow(ctx, sqlStr, scheduleId).Scan(&seconds)
+	if err != nil {
+		log.Println("GetTimeUntilShow Error:", err.Error())
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// GetOrderTicket returns the order with its show, movie, cinema and the most
+// recent payment attempt, if there is one.
+func (o OrderRepository) GetOrderTicket(ctx context.Context, db DBTX, orderId int) (model.OrderTicket, error) {
+	sqlStr := `
+		SELECT
+			o.id,
+			o.user_id,
+			o.booking_code,
+			o.total_price,
+			COALESCE(o.payment_status, 'pending'),
+			o.created_at,
+			s.id AS schedule_id,
+			s.show_date,
+			s.show_time,
+			s.price,
+			m.id AS movie_id,
+			m.title,
+			COALESCE(m.poster_url, '') AS poster_url,
+			m.duration,
+			c.id AS cinema_id,
+			c.name AS cinema_name,
+			COALESCE(c.logo_url, '') AS cinema_logo,
+			c.location AS cinema_location,
+			ci.name AS city_name,
+			a.name AS auditorium_name,
+			p.method AS payment_method,
+			p.provider AS payment_provider,
+			p.reference AS payment_reference,
+			p.status AS payment_state,
+			p.amount AS payment_amount
+		FROM orders o
+		INNER JOIN schedules s ON o.schedule_id = s.id
+		INNER JOIN movies m ON s.movie_id = m.id
+		INNER JOIN cinemas c ON s.cinema_id = c.id
+		INNER JOIN cities ci ON c.city_id = ci.id
+		INNER JOIN auditoriums a ON s.auditorium_id = a.id
+		LEFT JOIN LATERAL (
+			SELECT pm.name AS method, op.provider, op.reference, op.status, op.amount::int AS amount
+			FROM order_payments op
+			LEFT JOIN payment_methods pm ON op.payment_method_id = pm.id
+			WHERE op.order_id = o.id
+			ORDER BY op.transaction_time DESC, op.id DESC
+			LIMIT 1
+		) p ON true
+		WHERE o.id = $1`
+
+	var t model.OrderTicket
+	err := db.QueryRow(ctx, sqlStr, orderId).Scan(
+		&t.Id,
+		&t.UserId,
+		&t.BookingCode,
+		&t.TotalPrice,
+		&t.PaymentStatus,
+		&t.CreatedAt,
+		&t.ScheduleId,
+		&t.ShowDate,
+		&t.ShowTime,
+		&t.Price,
+		&t.MovieId,
+		&t.Title,
+		&t.PosterUrl,
+		&t.Duration,
+		&t.CinemaId,
+		&t.CinemaName,
+		&t.CinemaLogo,
+		&t.CinemaLocation,
+		&t.CityName,
+		&t.AuditoriumName,
+		&t.PaymentMethod,
+		&t.PaymentProvider,
+		&t.PaymentReference,
+		&t.PaymentState,
+		&t.PaymentAmount,
+	)
+	if err != nil {
+		log.Println("GetOrderTicket Error:", err.Error())
+		return model.OrderTicket{}, err
+	}
+	return t, nil
+}
+
+func (o OrderRepository) GetSeatsByOrderId(ctx context.Context, db DBTX, orderId int) ([]model.Seat, error) {
+	sqlStr := `
+		SELECT s.id, s.cinema_id, s.row_letter, s.seat_number, COALESCE(s.seat_type, 'regular'), COALESCE(od.price, sch.price)
+		FROM order_details od
+		INNER JOIN seats s ON od.seat_id = s.id
+		INNER JOIN orders o ON od.order_id = o.id
+		INNER JOIN schedules sch ON o.schedule_id = sch.id
+		WHERE od.order_id = $1
+		ORDER BY s.row_letter, s.seat_number`
+
+	rows, err := db.Query(ctx, sqlStr, orderId)
+	if err != nil {
+		log.Println("GetSeatsByOrderId Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []model.Seat
+	for rows.Next() {
+		var seat model.Seat
+		if err := rows.Scan(&seat.SeatId, &seat.CinemaId, &seat.RowLetter, &seat.SeatNumber, &seat.SeatType, &seat.Price); err != nil {
+			return nil, err
+		}
+		seats = append(seats, seat)
+	}
+	return seats, rows.Err()
+}
+
+func (o OrderRepository) GetOrderIdByBookingCode(ctx context.Context, db DBTX, bookingCode string) (int, error) {
+	sqlStr := "SELECT id FROM orders WHERE booking_code = $1"
+
+	var orderId int
+	err := db.QueryRow(ctx, sqlStr, bookingCode).Scan(&orderId)
+	if err != nil {
+		log.Println("GetOrderIdByBookingCode Error:", err.Error())
+		return 0, err
+	}
+	return orderId, nil
+}
+
+func (o OrderRepository) GetSeatAdmissions(ctx context.Context, db DBTX, orderId int) ([]model.SeatAdmission, error) {
+	sqlStr := `
+		SELECT s.id, s.row_letter, s.seat_number, COALESCE(s.seat_type, 'regular'), od.admitted_at, od.admitted_by
+		FROM order_details od
+		INNER JOIN seats s ON od.seat_id = s.id
+		WHERE od.order_id = $1
+		ORDER BY s.row_letter, s.seat_number`
+
+	rows, err := db.Query(ctx, sqlStr, orderId)
+	if err != nil {
+		log.Println("GetSeatAdmissions Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []model.SeatAdmission
+	for rows.Next() {
+		var seat model.SeatAdmission
+		if err := rows.Scan(&seat.SeatId, &seat.RowLetter, &seat.SeatNumber, &seat.SeatType, &seat.AdmittedAt, &seat.AdmittedBy); err != nil {
+			return nil, err
+		}
+		seats = append(seats, seat)
+	}
+	return seats, rows.Err()
+}
+
+// AdmitSeats marks the given seats of an order as admitted and returns the
+// seats that were actually admitted. Seats admitted before are left as is.
+func (o OrderRepository) AdmitSeats(ctx context.Context, db DBTX, orderId int, seatIds []int, admittedBy int) ([]int, error) {
+	sqlStr := `
+		UPDATE order_details
+		SET admitted_at = now(), admitted_by = $3
+		WHERE order_id = $1 AND seat_id = ANY($2::int[]) AND admitted_at IS NULL
+		RETURNING seat_id`
+
+	rows, err := db.Query(ctx, sqlStr, orderId, seatIds, admittedBy)
+	if err != nil {
+		log.Println("AdmitSeats Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admitted []int
+	for rows.Next() {
+		var seatId int
+		if err := rows.Scan(&seatId); err != nil {
+			return nil, err
+		}
+		admitted = append(admitted, seatId)
+	}
+	return admitted, rows.Err()
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type PasswordResetRepo interface {
+	InsertPasswordReset(ctx context.Context, db DBTX, reset model.PasswordReset) error
+	GetPasswordResetForUpdate(ctx context.Context, db DBTX, tokenHash string) (model.PasswordReset, error)
+	ExpireUserPasswordResets(ctx context.Context, db DBTX, userId int) error
+	SetUserPassword(ctx context.Context, db DBTX, userId int, hashedPassword string) error
+}
+
+type PasswordResetRepository struct{}
+
+func NewPasswordResetRepository() *PasswordResetRepository {
+	return &PasswordResetRepository{}
+}
+
+func (r PasswordResetRepository) InsertPasswordReset(ctx context.Context, db DBTX, reset model.PasswordReset) error {
+	sqlStr := `
+		INSERT INTO password_resets (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)`
+
+	_, err := db.Exec(ctx, sqlStr, reset.UserId, reset.TokenHash, reset.ExpiresAt)
+	if err != nil {
+		log.Println("InsertPasswordReset Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetPasswordResetForUpdate locks the reset until the transaction ends, so
+// its token can only be used once. Returns pgx.ErrNoRows for unknown tokens.
+func (r PasswordResetRepository) GetPasswordResetForUpdate(ctx context.Context, db DBTX, tokenHash string) (model.PasswordReset, error) {
+	sqlStr := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_resets
+		WHERE token_hash = $1
+		FOR UPDATE`
+
+	var p model.PasswordReset
+	err := db.QueryRow(ctx, sqlStr, tokenHash).Scan(
+		&p.Id,
+		&p.UserId,
+		&p.TokenHash,
+		&p.ExpiresAt,
+		&p.UsedAt,
+		&p.CreatedAt,
+	)
+	if err != nil {
+		log.Println("GetPasswordResetForUpdate Error:", err.Error())
+		return model.PasswordReset{}, err
+	}
+	return p, nil
+}
+
+// ExpireUserPasswordResets marks every unused reset of the user as used. It
+// runs before a new link is issued and once a reset is done, so at most the
+// newest link works.
+func (r PasswordResetRepository) ExpireUserPasswordResets(ctx context.Context, db DBTX, userId int) error {
+	sqlStr := "UPDATE password_resets SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL"
+	if _, err := db.Exec(ctx, sqlStr, userId); err != nil {
+		log.Println("ExpireUserPasswordResets Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r PasswordResetRepository) SetUserPassword(ctx context.Context, db DBTX, userId int, hashedPassword string) error {
+	tag, err := db.Exec(ctx, "UPDATE users SET password = $1, updated_at = now() WHERE id = $2", hashedPassword, userId)
+	if err != nil {
+		log.Println("SetUserPassword Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type PaymentRepo interface {
+	GetPaymentMethodByName(ctx context.Context, db DBTX, name string) (model.PaymentMethod, error)
+	InsertOrderPayment(ctx context.Context, db DBTX, payment model.OrderPayment) (int, error)
+	GetOrderPaymentByReference(ctx context.Context, db DBTX, reference string) (model.OrderPayment, error)
+	UpdateOrderPaymentStatus(ctx context.Context, db DBTX, paymentId int, fromStatus string, toStatus string, payload string) error
+	GetOrderPaymentsByStatus(ctx context.Context, db DBTX, orderId int, status string) ([]model.OrderPayment, error)
+	GetPaymentsByStatus(ctx context.Context, db DBTX, status string, limit int) ([]model.OrderPayment, error)
+}
+
+type PaymentRepository struct{}
+
+func NewPaymentRepository() *PaymentRepository {
+	return &PaymentRepository{}
+}
+
+func (p PaymentRepository) GetPaymentMethodByName(ctx context.Context, db DBTX, name string) (model.PaymentMethod, error) {
+	sqlStr := `
+		SELECT id, name, COALESCE(logo_url, '')
+		FROM payment_methods
+		WHERE LOWER(name) = LOWER($1)`
+
+	var method model.PaymentMethod
+	err := db.QueryRow(ctx, sqlStr, name).Scan(&method.Id, &method.Name, &method.LogoUrl)
+	if err != nil {
+		log.Println("GetPaymentMethodByName Error:", err.Error())
+		return model.PaymentMethod{}, err
+	}
+	return method, nil
+}
+
+func (p PaymentRepository) InsertOrderPayment(ctx context.Context, db DBTX, payment model.OrderPayment) (int, error) {
+	sqlStr := `
+		INSERT INTO order_payments (order_id, payment_method_id, provider, reference, amount, payment_url, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	var id int
+	err := db.QueryRow(ctx, sqlStr,
+		payment.OrderId,
+		payment.PaymentMethodId,
+		payment.Provider,
+		payment.Reference,
+		payment.Amount,
+		payment.PaymentUrl,
+		payment.Status,
+	).Scan(&id)
+	if err != nil {
+		log.Println("InsertOrderPayment Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+const orderPaymentSelect = `
+	SELECT
+		id,
+		order_id,
+		COALESCE(payment_method_id, 0),
+		COALESCE(provider, ''),
+		COALESCE(reference, ''),
+		COALESCE(amount, 0),
+		COALESCE(payment_url, ''),
+		COALESCE(status, 'pending'),
+		transaction_time
+	FROM order_payments`
+
+func (p PaymentRepository) GetOrderPaymentsByStatus(ctx context.Context, db DBTX, orderId int, status string) ([]model.OrderPayment, error) {
+	sqlStr := orderPaymentSelect + `
+		WHERE order_id = $1 AND status = $2
+		ORDER BY transaction_time`
+
+	return p.queryOrderPayments(ctx, db, sqlStr, orderId, status)
+}
+
+// GetPaymentsByStatus returns up to limit attempts of any order in the
+// status, oldest first.
+func (p PaymentRepository) GetPaymentsByStatus(ctx context.Context, db DBTX, status string, limit int) ([]model.OrderPayment, error) {
+	sqlStr := orderPaymentSelect + `
+		WHERE status = $1
+		ORDER BY updated_at, id
+		LIMIT $2`
+
+	return p.queryOrderPayments(ctx, db, sqlStr, status, limit)
+}
+
+func (p PaymentRepository) queryOrderPayments(ctx context.Context, db DBTX, sqlStr string, args ...any) ([]model.OrderPayment, error) {
+	rows, err := db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		log.Println("GetOrderPayments Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.OrderPayment
+	for rows.Next() {
+		var payment model.OrderPayment
+		err := rows.Scan(
+			&payment.Id,
+			&payment.OrderId,
+			&payment.PaymentMethodId,
+			&payment.Provider,
+			&payment.Reference,
+			&payment.Amount,
+			&payment.PaymentUrl,
+			&payment.Status,
+			&payment.TransactionTime,
+		)
+		if err != nil {
+			log.Println("GetOrderPayments Error:", err.Error())
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+func (p PaymentRepository) GetOrderPaymentByReference(ctx context.Context, db DBTX, reference string) (model.OrderPayment, error) {
+	sqlStr := `
+		SELECT
+			id,
+			order_id,
+			COALESCE(payment_method_id, 0),
+			COALESCE(provider, ''),
+			reference,
+			COALESCE(amount, 0),
+			COALESCE(payment_url, ''),
+			COALESCE(status, 'pending'),
+			transaction_time
+		FROM order_payments
+		WHERE reference = $1`
+
+	var payment model.OrderPayment
+	err := db.QueryRow(ctx, sqlStr, reference).Scan(
+		&payment.Id,
+		&payment.OrderId,
+		&payment.PaymentMethodId,
+		&payment.Provider,
+		&payment.Reference,
+		&payment.Amount,
+		&payment.PaymentUrl,
+		&payment.Status,
+		&payment.TransactionTime,
+	)
+	if err != nil {
+		log.Println("GetOrderPaymentByReference Error:", err.Error())
+		return model.OrderPayment{}, err
+	}
+	return payment, nil
+}
+
+func (p PaymentRepository) UpdateOrderPaymentStatus(ctx context.Context, db DBTX, paymentId int, fromStatus string, toStatus string, payload string) error {
+	sqlStr := `
+		UPDATE order_payments
+		SET status = $1, payload = COALESCE(NULLIF($2, ''), payload), updated_at = NOW()
+		WHERE id = $3 AND status = $4`
+
+	tag, err := db.Exec(ctx, sqlStr, toStatus, payload, paymentId, fromStatus)
+	if err != nil {
+		log.Println("UpdateOrderPaymentStatus Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type PointRepo interface {
+	InsertPointTransaction(ctx context.Context, db DBTX, trx model.PointTransaction) error
+	GetOrderPointsTotal(ctx context.Context, db DBTX, orderId int) (int, error)
+	GetPointBalanceForUpdate(ctx context.Context, db DBTX, userId int) (int, error)
+	GetPointBalance(ctx context.Context, db DBTX, userId int) (int, error)
+	GetPointTransactions(ctx context.Context, db DBTX, userId int) ([]model.PointTransaction, error)
+}
+
+type PointRepository struct{}
+
+func NewPointRepository() *PointRepository {
+	return &PointRepository{}
+}
+
+// InsertPointTransaction records a ledger entry and applies it to the
+// user's balance. Callers run it inside a transaction so both stay in sync.
+func (p PointRepository) InsertPointTransaction(ctx context.Context, db DBTX, trx model.PointTransaction) error {
+	sqlStr := `
+		INSERT INTO point_transactions (user_id, order_id, points_change, description)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := db.Exec(ctx, sqlStr, trx.UserId, trx.OrderId, trx.PointsChange, trx.Description)
+	if err != nil {
+		log.Println("InsertPointTransaction Error:", err.Error())
+		return err
+	}
+
+	sqlBalance := "UPDATE users SET loyalty_points = COALESCE(loyalty_points, 0) + $1, updated_at = NOW() WHERE id = $2"
+	_, err = db.Exec(ctx, sqlBalance, trx.PointsChange, trx.UserId)
+	if err != nil {
+		log.Println("UpdateLoyaltyPoints Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (p PointRepository) GetOrderPointsTotal(ctx context.Context, db DBTX, orderId int) (int, error) {
+	sqlStr := "SELECT COALESCE(SUM(points_change), 0) FROM point_transactions WHERE order_id = $1"
+	var total int
+	err := db.QueryRow(ctx, sqlStr, orderId).Scan(&total)
+	if err != nil {
+		log.Println("GetOrderPointsTotal Error:", err.Error())
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetPointBalanceForUpdate locks the user's row so the balance can not change
+// until the transaction ends.
+func (p PointRepository) GetPointBalanceForUpdate(ctx context.Context, db DBTX, userId int) (int, error) {
+	return p.getPointBalance(ctx, db, userId, "FOR UPDATE")
+}
+
+func (p PointRepository) GetPointBalance(ctx context.Context, db DBTX, userId int) (int, error) {
+	return p.getPointBalance(ctx, db, userId, "")
+}
+
+func (p PointRepository) getPointBalance(ctx context.Context, db DBTX, userId int, lock string) (int, error) {
+	sqlStr := "SELECT COALESCE(loyalty_points, 0) FROM users WHERE id = $1 " + lock
+	var balance int
+	err := db.QueryRow(ctx, sqlStr, userId).Scan(&balance)
+	if err != nil {
+		log.Println("GetPointBalance Error:", err.Error())
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (p PointRepository) GetPointTransactions(ctx context.Context, db DBTX, userId int) ([]model.PointTransaction, error) {
+	sqlStr := `
+		SELECT id, user_id, order_id, points_change, COALESCE(description, ''), created_at
+		FROM point_transactions
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := db.Query(ctx, sqlStr, userId)
+	if err != nil {
+		log.Println("GetPointTransactions Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []model.PointTransaction
+	for rows.Next() {
+		var t model.PointTransaction
+		if err := rows.Scan(&t.Id, &t.UserId, &t.OrderId, &t.PointsChange, &t.Description, &t.CreatedAt); err != nil {
+			log.Println("GetPointTransactions Error:", err.Error())
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	return transactions, rows.Err()
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type PricingRepo interface {
+	GetSeatTypePricesForSchedule(ctx context.Context, db DBTX, scheduleId int) (map[string]model.SeatTypePrice, error)
+	GetSeatTypePrices(ctx context.Context, db DBTX, cinemaId *int, scheduleId *int) ([]model.SeatTypePrice, error)
+	UpsertSeatTypePrice(ctx context.Context, db DBTX, price model.SeatTypePrice) (model.SeatTypePrice, error)
+	DeleteSeatTypePrice(ctx context.Context, db DBTX, id int) error
+}
+
+type PricingRepository struct{}
+
+func NewPricingRepository() *PricingRepository {
+	return &PricingRepository{}
+}
+
+const seatTypePriceColumns = "id, seat_type, cinema_id, schedule_id, multiplier::float8, surcharge::int, created_at, updated_at"
+
+func scanSeatTypePrice(row interface{ Scan(dest ...any) error }) (model.SeatTypePrice, error) {
+	var p model.SeatTypePrice
+	err := row.Scan(&p.Id, &p.SeatType, &p.CinemaId, &p.ScheduleId, &p.Multiplier, &p.Surcharge, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// GetSeatTypePricesForSchedule returns the rule that applies to each seat
+// type of a schedule: its own rule if it has one, otherwise the cinema's.
+func (p PricingRepository) GetSeatTypePricesForSchedule(ctx context.Context, db DBTX, scheduleId int) (map[string]model.SeatTypePrice, error) {
+	sqlStr := `
+		SELECT DISTINCT ON (p.seat_type) ` + seatTypePriceColumns + `
+		FROM seat_type_prices p
+		INNER JOIN schedules s ON s.id = $1
+		WHERE p.schedule_id = s.id OR p.cinema_id = s.cinema_id
+		ORDER BY p.seat_type, p.schedule_id IS NULL`
+
+	rows, err := db.Query(ctx, sqlStr, scheduleId)
+	if err != nil {
+		log.Println("GetSeatTypePricesForSchedule Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make(map[string]model.SeatTypePrice)
+	for rows.Next() {
+		price, err := scanSeatTypePrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices[price.SeatType] = price
+	}
+	return prices, rows.Err()
+}
+
+func (p PricingRepository) GetSeatTypePrices(ctx context.Context, db DBTX, cinemaId *int, scheduleId *int) ([]model.SeatTypePrice, error) {
+	sqlStr := `
+		SELECT ` + seatTypePriceColumns + `
+		FROM seat_type_prices
+		WHERE ($1::int IS NULL OR cinema_id = $1)
+			AND ($2::int IS NULL OR schedule_id = $2)
+		ORDER BY cinema_id NULLS LAST, schedule_id NULLS LAST, seat_type`
+
+	rows, err := db.Query(ctx, sqlStr, cinemaId, scheduleId)
+	if err != nil {
+		log.Println("GetSeatTypePrices Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []model.SeatTypePrice
+	for rows.Next() {
+		price, err := scanSeatTypePrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+func (p PricingRepository) UpsertSeatTypePrice(ctx context.Context, db DBTX, price model.SeatTypePrice) (model.SeatTypePrice, error) {
+	conflict := "(cinema_id, seat_type) WHERE cinema_id IS NOT NULL"
+	if price.ScheduleId != nil {
+		conflict = "(schedule_id, seat_type) WHERE schedule_id IS NOT NULL"
+	}
+
+	sqlStr := `
+		INSERT INTO seat_type_prices (seat_type, cinema_id, schedule_id, multiplier, surcharge)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ` + conflict + ` DO UPDATE
+		SET multiplier = EXCLUDED.multiplier, surcharge = EXCLUDED.surcharge, updated_at = now()
+		RETURNING ` + seatTypePriceColumns
+
+	saved, err := scanSeatTypePrice(db.QueryRow(ctx, sqlStr, price.SeatType, price.CinemaId, price.ScheduleId, price.Multiplier, price.Surcharge))
+	if err != nil {
+		log.Println("UpsertSeatTypePrice Error:", err.Error())
+		return model.SeatTypePrice{}, err
+	}
+	return saved, nil
+}
+
+func (p PricingRepository) DeleteSeatTypePrice(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM seat_type_prices WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteSeatTypePrice Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type RefreshTokenRepo interface {
+	InsertRefreshToken(ctx context.Context, db DBTX, token model.RefreshToken) error
+	GetRefreshTokenForUpdate(ctx context.Context, db DBTX, tokenHash string) (model.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, db DBTX, id int) error
+	RevokeRefreshTokenFamily(ctx context.Context, db DBTX, familyId string) error
+	RevokeUserRefreshTokens(ctx context.Context, db DBTX, userId int) error
+}
+
+type RefreshTokenRepository struct{}
+
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{}
+}
+
+func (r RefreshTokenRepository) InsertRefreshToken(ctx context.Context, db DBTX, token model.RefreshToken) error {
+	sqlStr := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := db.Exec(ctx, sqlStr, token.UserId, token.FamilyId, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		log.Println("InsertRefreshToken Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetRefreshTokenForUpdate locks the token until the transaction ends, so it
+// can only be exchanged once. Returns pgx.ErrNoRows for unknown tokens.
+func (r RefreshTokenRepository) GetRefreshTokenForUpdate(ctx context.Context, db DBTX, tokenHash string) (model.RefreshToken, error) {
+	sqlStr := `
+		SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE`
+
+	var t model.RefreshToken
+	err := db.QueryRow(ctx, sqlStr, tokenHash).Scan(
+		&t.Id,
+		&t.UserId,
+		&t.FamilyId,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		log.Println("GetRefreshTokenForUpdate Error:", err.Error())
+		return model.RefreshToken{}, err
+	}
+	return t, nil
+}
+
+func (r RefreshTokenRepository) MarkRefreshTokenUsed(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL", id)
+	if err != nil {
+		log.Println("MarkRefreshTokenUsed Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes every token of the family, the current
+// one included.
+func (r RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, db DBTX, familyId string) error {
+	sqlStr := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL"
+	if _, err := db.Exec(ctx, sqlStr, familyId); err != nil {
+		log.Println("RevokeRefreshTokenFamily Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user, logging
+// them out of all devices once their access tokens are gone as well.
+func (r RefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, db DBTX, userId int) error {
+	sqlStr := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL"
+	if _, err := db.Exec(ctx, sqlStr, userId); err != nil {
+		log.Println("RevokeUserRefreshTokens Error:", err.Error())
+		return err
+	}
+	return nil
+}
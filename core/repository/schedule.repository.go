@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/jackc/pgx/v5"
+)
+
+type ScheduleRepo interface {
+	GetSchedules(ctx context.Context, db DBTX, filter model.ScheduleFilter) ([]model.Schedule, error)
+	GetScheduleById(ctx context.Context, db DBTX, id int) (model.Schedule, error)
+	LockSchedule(ctx context.Context, db DBTX, id int) (model.Schedule, error)
+	CountOrdersForUpdate(ctx context.Context, db DBTX, scheduleId int) (model.ScheduleOrderCounts, error)
+	GetMovieDuration(ctx context.Context, db DBTX, movieId int) (int, error)
+	FindOverlap(ctx context.Context, db DBTX, auditoriumId int, startsAt time.Time, endsAt time.Time, bufferMinutes int, excludeId int) (*model.Schedule, error)
+	InsertSchedule(ctx context.Context, db DBTX, schedule model.Schedule) (int, error)
+	UpdateSchedule(ctx context.Context, db DBTX, schedule model.Schedule) error
+	DeleteSchedule(ctx context.Context, db DBTX, id int) error
+}
+
+type ScheduleRepository struct{}
+
+func NewScheduleRepository() *ScheduleRepository {
+	return &ScheduleRepository{}
+}
+
+const scheduleSelect = `
+	SELECT
+		s.id,
+		s.movie_id,
+		m.title,
+		s.cinema_id,
+		c.name,
+		a.id,
+		a.name,
+		s.show_date,
+		TO_CHAR(s.show_time, 'HH24:MI'),
+		s.show_date + s.show_time,
+		s.show_date + s.show_time + MAKE_INTERVAL(mins => m.duration),
+		s.price::int,
+		(SELECT COUNT(*) FROM orders o WHERE o.schedule_id = s.id AND o.payment_status = 'paid'),
+		s.created_at
+	FROM schedules s
+	INNER JOIN movies m ON s.movie_id = m.id
+	INNER JOIN cinemas c ON s.cinema_id = c.id
+	INNER JOIN auditoriums a ON s.auditorium_id = a.id`
+
+func scanSchedule(row interface{ Scan(dest ...any) error }) (model.Schedule, error) {
+	var s model.Schedule
+	err := row.Scan(
+		&s.Id,
+		&s.MovieId,
+		&s.MovieTitle,
+		&s.CinemaId,
+		&s.CinemaName,
+		&s.AuditoriumId,
+		&s.AuditoriumName,
+		&s.ShowDate,
+		&s.ShowTime,
+		&s.StartsAt,
+		&s.EndsAt,
+		&s.Price,
+		&s.PaidOrders,
+		&s.CreatedAt,
+	)
+	return s, err
+}
+
+func (r ScheduleRepository) GetSchedules(ctx context.Context, db DBTX, filter model.ScheduleFilter) ([]model.Schedule, error) {
+	sqlStr := scheduleSelect + `
+		WHERE ($1::int[] IS NULL OR s.id = ANY($1))
+			AND ($2::int IS NULL OR s.movie_id = $2)
+			AND ($3::int IS NULL OR s.cinema_id = $3)
+			AND ($4::int IS NULL OR s.auditorium_id = $4)
+			AND ($5::date IS NULL OR s.show_date >= $5)
+			AND ($6::date IS NULL OR s.show_date <= $6)
+		ORDER BY s.show_date, s.show_time, c.name, a.name`
+
+	rows, err := db.Query(ctx, sqlStr, filter.Ids, filter.MovieId, filter.CinemaId, filter.AuditoriumId, filter.DateFrom, filter.DateTo)
+	if err != nil {
+		log.Println("GetSchedules Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []model.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			log.Println("GetSchedules Error:", err.Error())
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r ScheduleRepository) GetScheduleById(ctx context.Context, db DBTX, id int) (model.Schedule, error) {
+	schedule, err := scanSchedule(db.QueryRow(ctx, scheduleSelect+" WHERE s.id = $1", id))
+	if err != nil {
+		log.Println("GetScheduleById Error:", err.Error())
+		return model.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// LockSchedule locks the schedule until the transaction ends, which also
+// keeps new orders from being placed on it, and returns its movie and
+// auditorium. Returns pgx.ErrNoRows when the schedule does not exist.
+func (r ScheduleRepository) LockSchedule(ctx context.Context, db DBTX, id int) (model.Schedule, error) {
+	var schedule model.Schedule
+	err := db.QueryRow(ctx, "SELECT id, movie_id, auditorium_id FROM schedules WHERE id = $1 FOR UPDATE", id).
+		Scan(&schedule.Id, &schedule.MovieId, &schedule.AuditoriumId)
+	if err != nil {
+		log.Println("LockSchedule Error:", err.Error())
+		return model.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// CountOrdersForUpdate locks the orders of the schedule, so none of them
+// can change status until the transaction ends, and returns how many are
+// paid and how many are still pending.
+func (r ScheduleRepository) CountOrdersForUpdate(ctx context.Context, db DBTX, scheduleId int) (model.ScheduleOrderCounts, error) {
+	sqlStr := `
+		SELECT
+			COUNT(*) FILTER (WHERE o.payment_status = 'paid'),
+			COUNT(*) FILTER (WHERE o.payment_status = 'pending')
+		FROM (
+			SELECT payment_status FROM orders WHERE schedule_id = $1 FOR UPDATE
+		) o`
+
+	var counts model.ScheduleOrderCounts
+	if err := db.QueryRow(ctx, sqlStr, scheduleId).Scan(&counts.Paid, &counts.Pending); err != nil {
+		log.Println("CountOrdersForUpdate Error:", err.Error())
+		return model.ScheduleOrderCounts{}, err
+	}
+	return counts, nil
+}
+
+func (r ScheduleRepository) GetMovieDuration(ctx context.Context, db DBTX, movieId int) (int, error) {
+	var duration int
+	err := db.QueryRow(ctx, "SELECT duration FROM movies WHERE id = $1", movieId).Scan(&duration)
+	if err != nil {
+		log.Println("GetMovieDuration Error:", err.Error())
+		return 0, err
+	}
+	return duration, nil
+}
+
+// FindOverlap returns the first show in the auditorium whose time, movie duration
+// plus bufferMinutes, overlaps startsAt to endsAt, or nil if there is none.
+// endsAt must include the buffer as well.
+func (r ScheduleRepository) FindOverlap(ctx context.Context, db DBTX, auditoriumId int, startsAt time.Time, endsAt time.Time, bufferMinutes int, excludeId int) (*model.Schedule, error) {
+	sqlStr := scheduleSelect + `
+		WHERE s.auditorium_id = $1
+			AND s.id <> $5
+			AND s.show_date + s.show_time < $3::timestamp
+			AND $2::timestamp < s.show_date + s.show_time + MAKE_INTERVAL(mins => m.duration + $4)
+		ORDER BY s.show_date, s.show_time
+		LIMIT 1`
+
+	schedule, err := scanSchedule(db.QueryRow(ctx, sqlStr, auditoriumId, startsAt, endsAt, bufferMinutes, excludeId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		log.Println("FindOverlap Error:", err.Error())
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r ScheduleRepository) InsertSchedule(ctx context.Context, db DBTX, schedule model.Schedule) (int, error) {
+	sqlStr := `
+		INSERT INTO schedules (movie_id, cinema_id, auditorium_id, show_date, show_time, price)
+		VALUES ($1, $2, $3, $4::timestamp::date, $4::timestamp::time, $5)
+		RETURNING id`
+
+	var id int
+	err := db.QueryRow(ctx, sqlStr, schedule.MovieId, schedule.CinemaId, schedule.AuditoriumId, schedule.StartsAt, schedule.Price).Scan(&id)
+	if err != nil {
+		log.Println("InsertSchedule Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r ScheduleRepository) UpdateSchedule(ctx context.Context, db DBTX, schedule model.Schedule) error {
+	sqlStr := `
+		UPDATE schedules SET
+			movie_id = $2,
+			cinema_id = $3,
+			auditorium_id = $4,
+			show_date = $5::timestamp::date,
+			show_time = $5::timestamp::time,
+			price = $6
+		WHERE id = $1`
+
+	tag, err := db.Exec(ctx, sqlStr, schedule.Id, schedule.MovieId, schedule.CinemaId, schedule.AuditoriumId, schedule.StartsAt, schedule.Price)
+	if err != nil {
+		log.Println("UpdateSchedule Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+func (r ScheduleRepository) DeleteSchedule(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM schedules WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteSchedule Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// seatEventBuffer is how many events a subscriber may fall behind before
+	// it is dropped. A dropped client reconnects and starts from a snapshot.
+	seatEventBuffer = 16
+	// seatEventSubscribeTimeout bounds the wait for Redis to confirm a new
+	// schedule subscription.
+	seatEventSubscribeTimeout = 5 * time.Second
+)
+
+// SeatEventRepository fans seat status changes out to every backend instance
+// through Redis pub/sub, one channel per schedule. Within an instance all
+// subscribers of a schedule share one Redis subscription, which is closed
+// when the last of them leaves.
+type SeatEventRepository struct {
+	redis *redis.Client
+	mu    sync.Mutex
+	feeds map[int]*seatFeed
+}
+
+// seatFeed is the shared Redis subscription of one schedule. ready is closed
+// once the subscription is active or err is set.
+type seatFeed struct {
+	pubsub      *redis.PubSub
+	ready       chan struct{}
+	err         error
+	subscribers map[chan string]struct{}
+}
+
+func NewSeatEventRepository(rdb *redis.Client) *SeatEventRepository {
+	return &SeatEventRepository{
+		redis: rdb,
+		feeds: make(map[int]*seatFeed),
+	}
+}
+
+func seatEventChannel(scheduleId int) string {
+	return fmt.Sprintf("bian:tickitz:seatevents:%d", scheduleId)
+}
+
+func (s *SeatEventRepository) Publish(ctx context.Context, scheduleId int, payload []byte) error {
+	return s.redis.Publish(ctx, seatEventChannel(scheduleId), payload).Err()
+}
+
+// Subscribe returns once the subscription is active, so no event published
+// after it returns is missed. The returned channel carries the raw payloads
+// and is closed when ctx is done or the subscriber falls too far behind.
+func (s *SeatEventRepository) Subscribe(ctx context.Context, scheduleId int) (<-chan string, error) {
+	s.mu.Lock()
+	feed, ok := s.feeds[scheduleId]
+	if !ok {
+		feed = &seatFeed{
+			pubsub:      s.redis.Subscribe(context.Background(), seatEventChannel(scheduleId)),
+			ready:       make(chan struct{}),
+			subscribers: make(map[chan string]struct{}),
+		}
+		s.feeds[scheduleId] = feed
+		go s.run(scheduleId, feed)
+	}
+	messages := make(chan string, seatEventBuffer)
+	feed.subscribers[messages] = struct{}{}
+	s.mu.Unlock()
+
+	select {
+	case <-feed.ready:
+	case <-ctx.Done():
+		s.unsubscribe(scheduleId, feed, messages)
+		return nil, ctx.Err()
+	}
+	if feed.err != nil {
+		s.unsubscribe(scheduleId, feed, messages)
+		return nil, feed.err
+	}
+
+	context.AfterFunc(ctx, func() {
+		s.unsubscribe(scheduleId, feed, messages)
+	})
+	return messages, nil
+}
+
+// run waits for Redis to confirm the subscription, then copies every message
+// to the subscribers of the feed until the feed is closed.
+func (s *SeatEventRepository) run(scheduleId int, feed *seatFeed) {
+	ctx, cancel := context.WithTimeout(context.Background(), seatEventSubscribeTimeout)
+	_, err := feed.pubsub.Receive(ctx)
+	cancel()
+	if err != nil {
+		s.mu.Lock()
+		feed.err = err
+		s.closeFeed(scheduleId, feed)
+		s.mu.Unlock()
+		close(feed.ready)
+		return
+	}
+	close(feed.ready)
+
+	for msg := range feed.pubsub.Channel() {
+		s.mu.Lock()
+		for messages := range feed.subscribers {
+			select {
+			case messages <- msg.Payload:
+			default:
+				s.dropSubscriber(scheduleId, feed, messages)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *SeatEventRepository) unsubscribe(scheduleId int, feed *seatFeed, messages chan string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropSubscriber(scheduleId, feed, messages)
+}
+
+// dropSubscriber closes the subscriber's channel and the feed once nobody
+// listens to it anymore. The caller holds s.mu.
+func (s *SeatEventRepository) dropSubscriber(scheduleId int, feed *seatFeed, messages chan string) {
+	if _, ok := feed.subscribers[messages]; !ok {
+		return
+	}
+	delete(feed.subscribers, messages)
+	close(messages)
+	if len(feed.subscribers) == 0 {
+		s.closeFeed(scheduleId, feed)
+	}
+}
+
+// closeFeed stops the Redis subscription of a feed that is still registered,
+// which ends its run loop. The caller holds s.mu.
+func (s *SeatEventRepository) closeFeed(scheduleId int, feed *seatFeed) {
+	if s.feeds[scheduleId] != feed {
+		return
+	}
+	delete(s.feeds, scheduleId)
+	feed.pubsub.Close()
+}
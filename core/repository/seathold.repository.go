@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// holdSeatsScript reserves every key for the given order, or none of them
+// when at least one key is already held by another order. It returns the
+// 1-based indexes of the keys that were already taken.
+var holdSeatsScript = redis.NewScript(`
+local taken = {}
+for i, key in ipairs(KEYS) do
+	local holder = redis.call('GET', key)
+	if holder and holder ~= ARGV[1] then
+		table.insert(taken, i)
+	end
+end
+if #taken > 0 then
+	return taken
+end
+for _, key in ipairs(KEYS) do
+	redis.call('SET', key, ARGV[1], 'PX', ARGV[2])
+end
+return taken
+`)
+
+// releaseSeatsScript only deletes keys that still belong to the given order,
+// so a late release never frees a seat that another order has held since.
+var releaseSeatsScript = redis.NewScript(`
+local released = 0
+for _, key in ipairs(KEYS) do
+	if redis.call('GET', key) == ARGV[1] then
+		released = released + redis.call('DEL', key)
+	end
+end
+return released
+`)
+
+type SeatHoldRepository struct {
+	redis *redis.Client
+}
+
+func NewSeatHoldRepository(rdb *redis.Client) *SeatHoldRepository {
+	return &SeatHoldRepository{
+		redis: rdb,
+	}
+}
+
+func seatHoldKey(scheduleId int, seatId int) string {
+	return fmt.Sprintf("bian:tickitz:seathold:%d:%d", scheduleId, seatId)
+}
+
+func seatHoldKeys(scheduleId int, seatIds []int) []string {
+	keys := make([]string, 0, len(seatIds))
+	for _, seatId := range seatIds {
+		keys = append(keys, seatHoldKey(scheduleId, seatId))
+	}
+	return keys
+}
+
+// HoldSeats reserves the seats of a schedule for an order and returns the
+// seats that are already held by another order. Nothing is reserved when
+// the returned slice is not empty.
+func (s SeatHoldRepository) HoldSeats(ctx context.Context, scheduleId int, orderId int, seatIds []int, ttl time.Duration) ([]int, error) {
+	if len(seatIds) == 0 {
+		return nil, nil
+	}
+
+	taken, err := holdSeatsScript.Run(ctx, s.redis, seatHoldKeys(scheduleId, seatIds), strconv.Itoa(orderId), ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []int
+	for _, idx := range taken {
+		conflicts = append(conflicts, seatIds[idx-1])
+	}
+	return conflicts, nil
+}
+
+func (s SeatHoldRepository) ReleaseSeats(ctx context.Context, scheduleId int, orderId int, seatIds []int) error {
+	if len(seatIds) == 0 {
+		return nil
+	}
+	return releaseSeatsScript.Run(ctx, s.redis, seatHoldKeys(scheduleId, seatIds), strconv.Itoa(orderId)).Err()
+}
+
+func (s SeatHoldRepository) GetHeldSeats(ctx context.Context, scheduleId int, seatIds []int) (map[int]bool, error) {
+	held := make(map[int]bool)
+	if len(seatIds) == 0 {
+		return held, nil
+	}
+
+	values, err := s.redis.MGet(ctx, seatHoldKeys(scheduleId, seatIds)...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		if v != nil {
+			held[seatIds[i]] = true
+		}
+	}
+	return held, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionRepo interface {
+	InsertSession(ctx context.Context, db DBTX, session model.Session) (int, error)
+	TouchSession(ctx context.Context, db DBTX, familyId string, ipAddress string, expiresAt time.Time) (int, error)
+	GetActiveSessions(ctx context.Context, db DBTX, userId int) ([]model.Session, error)
+	RevokeSession(ctx context.Context, db DBTX, userId int, id int) (model.Session, error)
+	RevokeSessionByFamily(ctx context.Context, db DBTX, familyId string) (int, error)
+	RevokeUserSessions(ctx context.Context, db DBTX, userId int) ([]model.Session, error)
+}
+
+type SessionRepository struct{}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{}
+}
+
+const sessionColumns = "id, user_id, family_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at"
+
+func scanSession(row interface{ Scan(dest ...any) error }) (model.Session, error) {
+	var s model.Session
+	err := row.Scan(
+		&s.Id,
+		&s.UserId,
+		&s.FamilyId,
+		&s.UserAgent,
+		&s.IpAddress,
+		&s.ExpiresAt,
+		&s.LastSeenAt,
+		&s.RevokedAt,
+		&s.CreatedAt,
+	)
+	return s, err
+}
+
+func (r SessionRepository) InsertSession(ctx context.Context, db DBTX, session model.Session) (int, error) {
+	sqlStr := `
+		INSERT INTO user_sessions (user_id, family_id, user_agent, ip_address, expires_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	var id int
+	err := db.QueryRow(ctx, sqlStr,
+		session.UserId,
+		session.FamilyId,
+		session.UserAgent,
+		session.IpAddress,
+		session.ExpiresAt,
+		time.Now().UTC(),
+	).Scan(&id)
+	if err != nil {
+		log.Println("InsertSession Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+// TouchSession records a refresh of the session's tokens and moves its
+// expiry along with the new refresh token. Returns pgx.ErrNoRows when the
+// session has been revoked.
+func (r SessionRepository) TouchSession(ctx context.Context, db DBTX, familyId string, ipAddress string, expiresAt time.Time) (int, error) {
+	sqlStr := `
+		UPDATE user_sessions
+		SET ip_address = COALESCE(NULLIF($2, ''), ip_address),
+			expires_at = $3,
+			last_seen_at = $4
+		WHERE family_id = $1 AND revoked_at IS NULL
+		RETURNING id`
+
+	var id int
+	err := db.QueryRow(ctx, sqlStr, familyId, ipAddress, expiresAt, time.Now().UTC()).Scan(&id)
+	if err != nil {
+		log.Println("TouchSession Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r SessionRepository) GetActiveSessions(ctx context.Context, db DBTX, userId int) ([]model.Session, error) {
+	sqlStr := `
+		SELECT ` + sessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC, id DESC`
+
+	rows, err := db.Query(ctx, sqlStr, userId, time.Now().UTC())
+	if err != nil {
+		log.Println("GetActiveSessions Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []model.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			log.Println("GetActiveSessions Error:", err.Error())
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeSession revokes a session of the user. Returns pgx.ErrNoRows when the
+// user has no such session or it was already revoked.
+func (r SessionRepository) RevokeSession(ctx context.Context, db DBTX, userId int, id int) (model.Session, error) {
+	sqlStr := `
+		UPDATE user_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		RETURNING ` + sessionColumns
+
+	session, err := scanSession(db.QueryRow(ctx, sqlStr, id, userId))
+	if err != nil {
+		log.Println("RevokeSession Error:", err.Error())
+		return model.Session{}, err
+	}
+	return session, nil
+}
+
+// RevokeSessionByFamily revokes the session a refresh token family belongs
+// to and returns its id, or 0 when it was already revoked.
+func (r SessionRepository) RevokeSessionByFamily(ctx context.Context, db DBTX, familyId string) (int, error) {
+	sqlStr := `
+		UPDATE user_sessions
+		SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+		RETURNING id`
+
+	var id int
+	err := db.QueryRow(ctx, sqlStr, familyId).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		log.Println("RevokeSessionByFamily Error:", err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r SessionRepository) RevokeUserSessions(ctx context.Context, db DBTX, userId int) ([]model.Session, error) {
+	sqlStr := `
+		UPDATE user_sessions
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING ` + sessionColumns
+
+	rows, err := db.Query(ctx, sqlStr, userId)
+	if err != nil {
+		log.Println("RevokeUserSessions Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []model.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			log.Println("RevokeUserSessions Error:", err.Error())
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+type VoucherRepo interface {
+	GetVouchers(ctx context.Context, db DBTX) ([]model.Voucher, error)
+	GetVoucherById(ctx context.Context, db DBTX, id int) (model.Voucher, error)
+	GetVoucherByCodeForUpdate(ctx context.Context, db DBTX, code string) (model.Voucher, error)
+	InsertVoucher(ctx context.Context, db DBTX, voucher model.Voucher) (model.Voucher, error)
+	UpdateVoucher(ctx context.Context, db DBTX, voucher model.Voucher) (model.Voucher, error)
+	DeleteVoucher(ctx context.Context, db DBTX, id int) error
+	CountVoucherUsages(ctx context.Context, db DBTX, voucherId int, userId int) (int, int, error)
+	InsertVoucherUsage(ctx context.Context, db DBTX, usage model.VoucherUsage) error
+	GetScheduleScope(ctx context.Context, db DBTX, scheduleId int) (model.ScheduleScope, error)
+}
+
+type VoucherRepository struct{}
+
+func NewVoucherRepository() *VoucherRepository {
+	return &VoucherRepository{}
+}
+
+const voucherColumns = `
+	id, code, COALESCE(description, ''), discount_type, discount_value::int, max_discount::int,
+	min_purchase::int, starts_at, ends_at, usage_limit, per_user_limit, movie_id, cinema_id, city_id,
+	is_active, (starts_at <= LOCALTIMESTAMP AND LOCALTIMESTAMP < ends_at) AS is_current, created_at, updated_at`
+
+func scanVoucher(row interface{ Scan(dest ...any) error }) (model.Voucher, error) {
+	var v model.Voucher
+	err := row.Scan(
+		&v.Id,
+		&v.Code,
+		&v.Description,
+		&v.DiscountType,
+		&v.DiscountValue,
+		&v.MaxDiscount,
+		&v.MinPurchase,
+		&v.StartsAt,
+		&v.EndsAt,
+		&v.UsageLimit,
+		&v.PerUserLimit,
+		&v.MovieId,
+		&v.CinemaId,
+		&v.CityId,
+		&v.IsActive,
+		&v.IsCurrent,
+		&v.CreatedAt,
+		&v.UpdatedAt,
+	)
+	return v, err
+}
+
+func (v VoucherRepository) GetVouchers(ctx context.Context, db DBTX) ([]model.Voucher, error) {
+	sqlStr := "SELECT " + voucherColumns + " FROM vouchers ORDER BY created_at DESC, id DESC"
+
+	rows, err := db.Query(ctx, sqlStr)
+	if err != nil {
+		log.Println("GetVouchers Error:", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vouchers []model.Voucher
+	for rows.Next() {
+		voucher, err := scanVoucher(rows)
+		if err != nil {
+			log.Println("GetVouchers Error:", err.Error())
+			return nil, err
+		}
+		vouchers = append(vouchers, voucher)
+	}
+	return vouchers, rows.Err()
+}
+
+func (v VoucherRepository) GetVoucherById(ctx context.Context, db DBTX, id int) (model.Voucher, error) {
+	sqlStr := "SELECT " + voucherColumns + " FROM vouchers WHERE id = $1"
+
+	voucher, err := scanVoucher(db.QueryRow(ctx, sqlStr, id))
+	if err != nil {
+		log.Println("GetVoucherById Error:", err.Error())
+		return model.Voucher{}, err
+	}
+	return voucher, nil
+}
+
+// GetVoucherByCodeForUpdate locks the voucher until the transaction ends, so
+// concurrent checkouts can not use it past its limits.
+func (v VoucherRepository) GetVoucherByCodeForUpdate(ctx context.Context, db DBTX, code string) (model.Voucher, error) {
+	sqlStr := "SELECT " + voucherColumns + " FROM vouchers WHERE UPPER(code) = UPPER($1) FOR UPDATE"
+
+	voucher, err := scanVoucher(db.QueryRow(ctx, sqlStr, code))
+	if err != nil {
+		log.Println("GetVoucherByCodeForUpdate Error:", err.Error())
+		return model.Voucher{}, err
+	}
+	return voucher, nil
+}
+
+func (v VoucherRepository) InsertVoucher(ctx context.Context, db DBTX, voucher model.Voucher) (model.Voucher, error) {
+	sqlStr := `
+		INSERT INTO vouchers (
+			code, description, discount_type, discount_value, max_discount, min_purchase,
+			starts_at, ends_at, usage_limit, per_user_limit, movie_id, cinema_id, city_id, is_active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING ` + voucherColumns
+
+	saved, err := scanVoucher(db.QueryRow(ctx, sqlStr,
+		voucher.Code,
+		voucher.Description,
+		voucher.DiscountType,
+		voucher.DiscountValue,
+		voucher.MaxDiscount,
+		voucher.MinPurchase,
+		voucher.StartsAt,
+		voucher.EndsAt,
+		voucher.UsageLimit,
+		voucher.PerUserLimit,
+		voucher.MovieId,
+		voucher.CinemaId,
+		voucher.CityId,
+		voucher.IsActive,
+	))
+	if err != nil {
+		log.Println("InsertVoucher Error:", err.Error())
+		return model.Voucher{}, err
+	}
+	return saved, nil
+}
+
+func (v VoucherRepository) UpdateVoucher(ctx context.Context, db DBTX, voucher model.Voucher) (model.Voucher, error) {
+	sqlStr := `
+		UPDATE vouchers SET
+			code = $2,
+			description = $3,
+			discount_type = $4,
+			discount_value = $5,
+			max_discount = $6,
+			min_purchase = $7,
+			starts_at = $8,
+			ends_at = $9,
+			usage_limit = $10,
+			per_user_limit = $11,
+			movie_id = $12,
+			cinema_id = $13,
+			city_id = $14,
+			is_active = $15,
+			updated_at = now()
+		WHERE id = $1
+		RETURNING ` + voucherColumns
+
+	saved, err := scanVoucher(db.QueryRow(ctx, sqlStr,
+		voucher.Id,
+		voucher.Code,
+		voucher.Description,
+		voucher.DiscountType,
+		voucher.DiscountValue,
+		voucher.MaxDiscount,
+		voucher.MinPurchase,
+		voucher.StartsAt,
+		voucher.EndsAt,
+		voucher.UsageLimit,
+		voucher.PerUserLimit,
+		voucher.MovieId,
+		voucher.CinemaId,
+		voucher.CityId,
+		voucher.IsActive,
+	))
+	if err != nil {
+		log.Println("UpdateVoucher Error:", err.Error())
+		return model.Voucher{}, err
+	}
+	return saved, nil
+}
+
+func (v VoucherRepository) DeleteVoucher(ctx context.Context, db DBTX, id int) error {
+	tag, err := db.Exec(ctx, "DELETE FROM vouchers WHERE id = $1", id)
+	if err != nil {
+		log.Println("DeleteVoucher Error:", err.Error())
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.ErrNoRowsUpdated
+	}
+	return nil
+}
+
+// CountVoucherUsages returns how often the voucher is in use overall and by
+// the user. Usages of orders that were cancelled, expired, failed or
+// refunded do not count.
+func (v VoucherRepository) CountVoucherUsages(ctx context.Context, db DBTX, voucherId int, userId int) (int, int, error) {
+	sqlStr := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE vu.user_id = $2)
+		FROM voucher_usages vu
+		INNER JOIN orders o ON vu.order_id = o.id
+		WHERE vu.voucher_id = $1
+			AND o.payment_status IN ('pending', 'paid')`
+
+	var total, byUser int
+	err := db.QueryRow(ctx, sqlStr, voucherId, userId).Scan(&total, &byUser)
+	if err != nil {
+		log.Println("CountVoucherUsages Error:", err.Error())
+		return 0, 0, err
+	}
+	return total, byUser, nil
+}
+
+func (v VoucherRepository) InsertVoucherUsage(ctx context.Context, db DBTX, usage model.VoucherUsage) error {
+	sqlStr := `
+		INSERT INTO voucher_usages (voucher_id, user_id, order_id, discount)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := db.Exec(ctx, sqlStr, usage.VoucherId, usage.UserId, usage.OrderId, usage.Discount)
+	if err != nil {
+		log.Println("InsertVoucherUsage Error:", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (v VoucherRepository) GetScheduleScope(ctx context.Context, db DBTX, scheduleId int) (model.ScheduleScope, error) {
+	sqlStr := `
+		SELECT s.movie_id, s.cinema_id, c.city_id
+		FROM schedules s
+		INNER JOIN cinemas c ON s.cinema_id = c.id
+		WHERE s.id = $1`
+
+	var scope model.ScheduleScope
+	err := db.QueryRow(ctx, sqlStr, scheduleId).Scan(&scope.MovieId, &scope.CinemaId, &scope.CityId)
+	if err != nil {
+		log.Println("GetScheduleScope Error:", err.Error())
+		return model.ScheduleScope{}, err
+	}
+	return scope, nil
+}
@@ -10,10 +10,17 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func RegisterAdminRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
+func RegisterAdminRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, orderService *service.OrderService) {
 	adminRepository := repository.NewAdminRepository(db)
 	adminService := service.NewAdminService(adminRepository)
 	adminController := controller.NewAdminController(adminService)
+	orderController := controller.NewOrderController(orderService)
+	pricingController := controller.NewPricingController(service.NewPricingService(repository.NewPricingRepository(), db))
+	voucherController := controller.NewVoucherController(service.NewVoucherService(repository.NewVoucherRepository(), db))
+	cinemaController := controller.NewCinemaController(service.NewCinemaService(repository.NewCinemaRepository(), db))
+	catalogController := controller.NewCatalogController(service.NewCatalogService(repository.NewCatalogRepository(), db))
+	scheduleController := controller.NewScheduleController(service.NewScheduleService(repository.NewScheduleRepository(), repository.NewCinemaRepository(), db))
+	loginThrottleController := controller.NewLoginThrottleController(newLoginThrottleService(rdb))
 
 	g := app.Group("/admin")
 	g.Use(middleware.VerifyToken(rdb))
@@ -23,5 +30,51 @@ func RegisterAdminRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
 		g.POST("/movies", adminController.CreateMovieAdmin)
 		g.DELETE("/movies/:id", adminController.DeleteMovieAdmin)
 		g.PATCH("/movies/:id", adminController.UpdateMovieAdmin)
+		g.GET("/movies/:id/cast", catalogController.GetMovieCast)
+		g.PUT("/movies/:id/cast", catalogController.SaveMovieCast)
+		g.GET("/movies/:id/genres", catalogController.GetMovieGenres)
+		g.PUT("/movies/:id/genres", catalogController.SaveMovieGenres)
+		g.GET("/directors", catalogController.GetDirectors)
+		g.POST("/directors", catalogController.CreateDirector)
+		g.PUT("/directors/:id", catalogController.UpdateDirector)
+		g.DELETE("/directors/:id", catalogController.DeleteDirector)
+		g.GET("/actors", catalogController.GetActors)
+		g.POST("/actors", catalogController.CreateActor)
+		g.PUT("/actors/:id", catalogController.UpdateActor)
+		g.DELETE("/actors/:id", catalogController.DeleteActor)
+		g.GET("/genres", catalogController.GetGenres)
+		g.POST("/genres", catalogController.CreateGenre)
+		g.PUT("/genres/:id", catalogController.UpdateGenre)
+		g.DELETE("/genres/:id", catalogController.DeleteGenre)
+		g.POST("/orders/:id/cancel", orderController.CancelOrderAdmin)
+		g.GET("/seat-prices", pricingController.GetSeatTypePrices)
+		g.PUT("/seat-prices", pricingController.SaveSeatTypePrice)
+		g.DELETE("/seat-prices/:id", pricingController.DeleteSeatTypePrice)
+		g.GET("/vouchers", voucherController.GetVouchers)
+		g.POST("/vouchers", voucherController.CreateVoucher)
+		g.GET("/vouchers/:id", voucherController.GetVoucher)
+		g.PUT("/vouchers/:id", voucherController.UpdateVoucher)
+		g.DELETE("/vouchers/:id", voucherController.DeleteVoucher)
+		g.GET("/cities", cinemaController.GetCities)
+		g.POST("/cities", cinemaController.CreateCity)
+		g.PUT("/cities/:id", cinemaController.UpdateCity)
+		g.DELETE("/cities/:id", cinemaController.DeleteCity)
+		g.GET("/cinemas", cinemaController.GetCinemas)
+		g.POST("/cinemas", cinemaController.CreateCinema)
+		g.GET("/cinemas/:id", cinemaController.GetCinema)
+		g.PATCH("/cinemas/:id", cinemaController.UpdateCinema)
+		g.DELETE("/cinemas/:id", cinemaController.DeleteCinema)
+		g.GET("/cinemas/:id/auditoriums", cinemaController.GetAuditoriums)
+		g.POST("/cinemas/:id/auditoriums", cinemaController.CreateAuditorium)
+		g.PUT("/auditoriums/:id", cinemaController.UpdateAuditorium)
+		g.DELETE("/auditoriums/:id", cinemaController.DeleteAuditorium)
+		g.GET("/auditoriums/:id/seats", cinemaController.GetSeatLayout)
+		g.PUT("/auditoriums/:id/seats", cinemaController.SaveSeatLayout)
+		g.GET("/schedules", scheduleController.GetSchedules)
+		g.POST("/schedules", scheduleController.CreateSchedules)
+		g.GET("/schedules/:id", scheduleController.GetSchedule)
+		g.PUT("/schedules/:id", scheduleController.UpdateSchedule)
+		g.DELETE("/schedules/:id", scheduleController.DeleteSchedule)
+		g.POST("/login-lockouts/unlock", loginThrottleController.UnlockLogin)
 	}
 }
@@ -5,18 +5,37 @@ import (
 	"github.com/Albaihaqi354/Tickitz-BE/core/middleware"
 	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
 	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func RegisterAuthRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
+func newSessionService(db *pgxpool.Pool, rdb *redis.Client) *service.SessionService {
+	return service.NewSessionService(repository.NewSessionRepository(), repository.NewRefreshTokenRepository(), repository.NewAuthRepository(db, rdb), db)
+}
+
+func newLoginThrottleService(rdb *redis.Client) *service.LoginThrottleService {
+	return service.NewLoginThrottleService(repository.NewLoginAttemptRepository(rdb))
+}
+
+func newMailThrottleService(rdb *redis.Client) *service.MailThrottleService {
+	return service.NewMailThrottleService(repository.NewMailThrottleRepository(rdb))
+}
+
+func RegisterAuthRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, mailer pkg.Mailer) {
 	authRepository := repository.NewAuthRepository(db, rdb)
-	authService := service.NewAuthService(authRepository, rdb)
+	sessionService := newSessionService(db, rdb)
+	authService := service.NewAuthService(authRepository, repository.NewRefreshTokenRepository(), repository.NewPasswordResetRepository(), sessionService, newLoginThrottleService(rdb), newMailThrottleService(rdb), mailer, db, rdb)
 	authController := controller.NewAuthController(authService)
 
 	g := app.Group("/auth")
 	g.POST("/register", authController.Register)
 	g.POST("/login", authController.Login)
+	g.POST("/refresh", authController.Refresh)
+	g.GET("/verify", authController.VerifyEmail)
+	g.POST("/verify/resend", authController.ResendVerification)
+	g.POST("/forgot-password", authController.ForgotPassword)
+	g.POST("/reset-password", authController.ResetPassword)
 	g.DELETE("/logout", middleware.VerifyToken(rdb), authController.Logout)
 }
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/Albaihaqi354/Tickitz-BE/core/controller"
+	"github.com/Albaihaqi354/Tickitz-BE/core/middleware"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func RegisterCheckinRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
+	checkinService := service.NewCheckinService(repository.NewOrdersRepository(), db)
+	checkinController := controller.NewCheckinController(checkinService)
+
+	app.POST("/checkin", middleware.VerifyToken(rdb), middleware.CheckRole(middleware.RoleStaff, middleware.RoleAdmin), checkinController.Checkin)
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	_ "github.com/Albaihaqi354/Tickitz-BE/docs"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -11,10 +12,13 @@ import (
 	"context"
 )
 
-func Init(app *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+func Init(app *gin.Engine, db *pgxpool.Pool, rdb *redis.Client, provider pkg.PaymentProvider, mailer pkg.Mailer) {
+	orderService := NewOrderService(db, rdb, provider)
+
 	app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	app.Static("/profile", "./public/profile")
 	app.Static("/movie", "./public/movie")
+	app.Static("/cinema", "./public/cinema")
 
 	// Register health at both root and /api for verification
 	app.GET("/health", func(c *gin.Context) {
@@ -43,17 +47,21 @@ func Init(app *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 			})
 		})
 
-		RegisterAuthRouter(api, db, rdb)
+		RegisterAuthRouter(api, db, rdb, mailer)
 		RegisterMovieRouter(api, db, rdb)
-		RegisterAdminRouter(api, db, rdb)
+		RegisterAdminRouter(api, db, rdb, orderService)
 		RegisterUserRouter(api, db, rdb)
-		RegisterOrderRouter(api, db, rdb)
+		RegisterOrderRouter(api, db, rdb, orderService)
+		RegisterPaymentRouter(api, orderService, provider)
+		RegisterCheckinRouter(api, db, rdb)
 	}
 
 	// ALSO register them at root for frontend that hits /movies DIRECTLY
-	RegisterAuthRouter(app, db, rdb)
+	RegisterAuthRouter(app, db, rdb, mailer)
 	RegisterMovieRouter(app, db, rdb)
-	RegisterAdminRouter(app, db, rdb)
+	RegisterAdminRouter(app, db, rdb, orderService)
 	RegisterUserRouter(app, db, rdb)
-	RegisterOrderRouter(app, db, rdb)
+	RegisterOrderRouter(app, db, rdb, orderService)
+	RegisterPaymentRouter(app, orderService, provider)
+	RegisterCheckinRouter(app, db, rdb)
 }
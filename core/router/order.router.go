@@ -5,22 +5,44 @@ import (
 	"github.com/Albaihaqi354/Tickitz-BE/core/middleware"
 	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
 	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func RegisterOrderRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
+// NewOrderService wires an OrderService with all of its dependencies. The
+// routes and the order expiry worker share it, so both see the same setup.
+func NewOrderService(db *pgxpool.Pool, rdb *redis.Client, provider pkg.PaymentProvider) *service.OrderService {
 	orderRepository := repository.NewOrdersRepository()
-	orderService := service.NewOrderService(orderRepository, db)
+	seatHoldRepository := repository.NewSeatHoldRepository(rdb)
+	seatEventRepository := repository.NewSeatEventRepository(rdb)
+	pointService := service.NewPointService(repository.NewPointRepository(), db)
+	pricingRepository := repository.NewPricingRepository()
+	voucherService := service.NewVoucherService(repository.NewVoucherRepository(), db)
+	paymentRepository := repository.NewPaymentRepository()
+	paymentService := service.NewPaymentService(paymentRepository, provider, db)
+	return service.NewOrderService(orderRepository, seatHoldRepository, seatEventRepository, pointService, pricingRepository, voucherService, paymentService, db)
+}
+
+// maxSeatStreamsPerIP caps the open seat map streams of one client address.
+const maxSeatStreamsPerIP = 5
+
+func RegisterOrderRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, orderService *service.OrderService) {
 	orderController := controller.NewOrderController(orderService)
+	authRepository := repository.NewAuthRepository(db, rdb)
 
 	g := app.Group("/orders")
 	{
 		g.GET("/schedules/:id", orderController.GetSchedules)
 		g.GET("/seats/:id", orderController.GetSeats)
+		g.GET("/seats/:id/stream", middleware.LimitConcurrentPerIP(maxSeatStreamsPerIP), orderController.StreamSeats)
 
-		g.POST("/", middleware.VerifyToken(rdb), middleware.CheckRole("user"), orderController.CreateOrder)
-		g.PATCH("/:id", middleware.VerifyToken(rdb), middleware.CheckRole("user"), orderController.UpdatePaymentStatus)
+		g.POST("/", middleware.VerifyToken(rdb), middleware.CheckRole("user"), middleware.RequireVerifiedEmail(authRepository), middleware.Idempotency(rdb), orderController.CreateOrder)
+		g.GET("/:id", middleware.VerifyToken(rdb), middleware.CheckRole("user", "admin"), orderController.GetOrderDetail)
+		g.PATCH("/:id", middleware.VerifyToken(rdb), middleware.CheckRole("user", "admin"), middleware.Idempotency(rdb), orderController.UpdatePaymentStatus)
+		g.GET("/:id/ticket.png", middleware.VerifyToken(rdb), middleware.CheckRole("user", "admin"), orderController.GetTicketQRCode)
+		g.GET("/:id/ticket.pdf", middleware.VerifyToken(rdb), middleware.CheckRole("user"), orderController.GetTicketPDF)
+		g.POST("/:id/cancel", middleware.VerifyToken(rdb), middleware.CheckRole("user"), orderController.CancelOrder)
 	}
 }
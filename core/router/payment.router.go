@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/Albaihaqi354/Tickitz-BE/core/controller"
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPaymentRouter only exposes the payment simulator when provider is
+// the simulated one.
+func RegisterPaymentRouter(app gin.IRouter, orderService *service.OrderService, provider pkg.PaymentProvider) {
+	paymentController := controller.NewPaymentController(orderService)
+
+	g := app.Group("/payments")
+	g.POST("/webhook", paymentController.Webhook)
+	if _, ok := provider.(*pkg.SimulatedProvider); ok {
+		g.POST("/simulate/:reference", paymentController.SimulatePayment)
+	}
+}
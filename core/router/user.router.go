@@ -12,8 +12,11 @@ import (
 
 func RegisterUserRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
 	userRepository := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepository)
+	sessionService := newSessionService(db, rdb)
+	userService := service.NewUserService(userRepository, sessionService)
 	userController := controller.NewUserController(userService)
+	pointController := controller.NewPointController(service.NewPointService(repository.NewPointRepository(), db))
+	sessionController := controller.NewSessionController(sessionService)
 
 	g := app.Group("/user")
 	g.Use(middleware.VerifyToken(rdb))
@@ -21,7 +24,11 @@ func RegisterUserRouter(app gin.IRouter, db *pgxpool.Pool, rdb *redis.Client) {
 	{
 		g.GET("/", userController.GetProfile)
 		g.GET("/history", userController.GetHistory)
+		g.GET("/points", pointController.GetPoints)
 		g.PATCH("/password", userController.UpdatePassword)
 		g.PATCH("/profile", userController.UpdateProfile)
+		g.GET("/sessions", sessionController.GetSessions)
+		g.DELETE("/sessions", sessionController.RevokeAllSessions)
+		g.DELETE("/sessions/:id", sessionController.RevokeSession)
 	}
 }
@@ -3,27 +3,75 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"os"
 	"time"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
 	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
 	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
 type AuthService struct {
-	authRepository *repository.AuthRepository
-	redis          *redis.Client
+	authRepository          *repository.AuthRepository
+	refreshTokenRepository  repository.RefreshTokenRepo
+	passwordResetRepository repository.PasswordResetRepo
+	sessionService          *SessionService
+	loginThrottleService    *LoginThrottleService
+	mailThrottleService     *MailThrottleService
+	mailer                  pkg.Mailer
+	db                      *pgxpool.Pool
+	redis                   *redis.Client
 }
 
-func NewAuthService(authRepository *repository.AuthRepository, rdb *redis.Client) *AuthService {
+func NewAuthService(authRepository *repository.AuthRepository, refreshTokenRepository repository.RefreshTokenRepo, passwordResetRepository repository.PasswordResetRepo, sessionService *SessionService, loginThrottleService *LoginThrottleService, mailThrottleService *MailThrottleService, mailer pkg.Mailer, db *pgxpool.Pool, rdb *redis.Client) *AuthService {
 	return &AuthService{
-		authRepository: authRepository,
-		redis:          rdb,
+		authRepository:          authRepository,
+		refreshTokenRepository:  refreshTokenRepository,
+		passwordResetRepository: passwordResetRepository,
+		sessionService:          sessionService,
+		loginThrottleService:    loginThrottleService,
+		mailThrottleService:     mailThrottleService,
+		mailer:                  mailer,
+		db:                      db,
+		redis:                   rdb,
 	}
 }
 
+// emailVerificationTTL is how long a verification link stays valid.
+func emailVerificationTTL() time.Duration {
+	return pkg.GetEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+}
+
+// emailVerificationURL is the GET /auth/verify endpoint as users reach it.
+func emailVerificationURL() string {
+	if u := os.Getenv("EMAIL_VERIFICATION_URL"); u != "" {
+		return u
+	}
+	return "http://localhost:8080/auth/verify"
+}
+
+// passwordResetTTL is how long a password reset link stays valid.
+func passwordResetTTL() time.Duration {
+	return pkg.GetEnvDuration("PASSWORD_RESET_TTL", time.Hour)
+}
+
+// passwordResetURL is the frontend page that asks for the new password and
+// posts it with the token to POST /auth/reset-password.
+func passwordResetURL() string {
+	if u := os.Getenv("PASSWORD_RESET_URL"); u != "" {
+		return u
+	}
+	return "http://localhost:5173/reset-password"
+}
+
 func (a AuthService) Register(ctx context.Context, newUser dto.NewUser) (dto.RegisterResponse, error) {
 	hc := pkg.HashConfig{}
 	hc.UseRecomended()
@@ -40,6 +88,11 @@ func (a AuthService) Register(ctx context.Context, newUser dto.NewUser) (dto.Reg
 		return dto.RegisterResponse{}, err
 	}
 
+	// The account exists either way; the user can ask for another link.
+	if err := a.sendVerification(ctx, data); err != nil {
+		log.Println("Error send verification mail: ", err.Error())
+	}
+
 	response := dto.RegisterResponse{
 		Id:    data.Id,
 		Email: data.Email,
@@ -49,10 +102,21 @@ func (a AuthService) Register(ctx context.Context, newUser dto.NewUser) (dto.Reg
 	return response, nil
 }
 
+// Login is throttled per email and per IP address: after a few failed
+// attempts every further failure locks them for exponentially longer, up to a
+// lockout, and attempts made while locked fail with a
+// *apperr.LoginThrottledError without checking the password.
 func (a AuthService) Login(ctx context.Context, loginReq dto.LoginRequest) (dto.LoginResponse, error) {
+	if err := a.loginThrottleService.Check(ctx, loginReq.Email, loginReq.IpAddress); err != nil {
+		return dto.LoginResponse{}, err
+	}
+
 	user, err := a.authRepository.FindUserByEmail(ctx, loginReq.Email)
 	if err != nil {
 		log.Println(err.Error())
+		if errors.Is(err, pgx.ErrNoRows) {
+			a.recordLoginFailure(ctx, loginReq)
+		}
 		return dto.LoginResponse{}, errors.New("invalid email or password")
 	}
 
@@ -66,32 +130,404 @@ func (a AuthService) Login(ctx context.Context, loginReq dto.LoginRequest) (dto.
 	}
 
 	if !isValid {
+		a.recordLoginFailure(ctx, loginReq)
 		return dto.LoginResponse{}, errors.New("invalid email or password")
 	}
 
-	jwtClaim := pkg.NewJWTClaim(user.Id, user.Email, user.Role)
+	if err := a.loginThrottleService.RecordSuccess(ctx, loginReq.Email); err != nil {
+		log.Println("Error reset login attempts: ", err.Error())
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return dto.LoginResponse{}, apperr.ErrEmailNotVerified
+	}
+
+	familyId, err := pkg.NewOpaqueToken(16)
+	if err != nil {
+		log.Println(err.Error())
+		return dto.LoginResponse{}, errors.New("internal server error")
+	}
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		log.Println(err.Error())
+		return dto.LoginResponse{}, errors.New("internal server error")
+	}
+	defer tx.Rollback(ctx)
+
+	refreshToken, expiresAt, err := a.newRefreshToken(ctx, tx, user.Id, familyId)
+	if err != nil {
+		log.Println("Error save refresh token: ", err.Error())
+		return dto.LoginResponse{}, errors.New("internal server error")
+	}
+	sessionId, err := a.sessionService.StartSession(ctx, tx, user.Id, familyId, loginReq.UserAgent, loginReq.IpAddress, expiresAt)
+	if err != nil {
+		log.Println("Error save session: ", err.Error())
+		return dto.LoginResponse{}, errors.New("internal server error")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println(err.Error())
+		return dto.LoginResponse{}, errors.New("internal server error")
+	}
+
+	return a.loginResponse(ctx, user, sessionId, refreshToken)
+}
+
+// recordLoginFailure counts a failed login. A failure to count it is only
+// logged; the caller reports the failed login either way.
+func (a AuthService) recordLoginFailure(ctx context.Context, loginReq dto.LoginRequest) {
+	if err := a.loginThrottleService.RecordFailure(ctx, loginReq.Email, loginReq.IpAddress); err != nil {
+		log.Println("Error record login failure: ", err.Error())
+	}
+}
+
+// VerifyEmail marks the email in a verification token as verified. Tokens
+// stay usable until they expire, so opening a link twice is harmless.
+func (a AuthService) VerifyEmail(ctx context.Context, token string) error {
+	verification, err := pkg.ParseEmailVerification(token)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNoVerificationSecret) {
+			log.Println("Service Error (VerifyEmail):", err.Error())
+			return err
+		}
+		return apperr.ErrInvalidVerificationToken
+	}
+
+	ok, err := a.authRepository.MarkEmailVerified(ctx, verification.UserId, verification.Email)
+	if err != nil {
+		log.Println("Service Error (VerifyEmail):", err.Error())
+		return err
+	}
+	if !ok {
+		return apperr.ErrInvalidVerificationToken
+	}
+	return nil
+}
+
+// ResendVerification mails a new verification link to an unverified account.
+// It succeeds silently for unknown or verified emails, and when the mail can
+// not be sent, so it can not be used to find out which emails have an
+// account. Requests are throttled by the MailThrottleService.
+func (a AuthService) ResendVerification(ctx context.Context, req dto.ResendVerificationRequest) error {
+	allowed, err := a.mailThrottleService.Allow(ctx, "verification", req.Email, req.IpAddress)
+	if err != nil || !allowed {
+		return err
+	}
+
+	user, err := a.authRepository.FindUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		log.Println("Service Error (ResendVerification):", err.Error())
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := a.sendVerification(ctx, user); err != nil {
+		log.Println("Service Error (ResendVerification):", err.Error())
+	}
+	return nil
+}
+
+func (a AuthService) sendVerification(ctx context.Context, user model.User) error {
+	expiresAt := time.Now().Add(emailVerificationTTL())
+	token, err := pkg.SignEmailVerification(user.Id, user.Email, expiresAt)
+	if err != nil {
+		return err
+	}
+	link := emailVerificationURL() + "?token=" + url.QueryEscape(token)
+
+	return a.mailer.Send(ctx, pkg.Mail{
+		To:      user.Email,
+		Subject: "Verify your Tickitz account",
+		Body: fmt.Sprintf("Hi,\n\nPlease verify your email address by opening the link below before %s:\n\n%s\n\nIf you did not create a Tickitz account, you can ignore this email.\n",
+			expiresAt.Format("2006-01-02 15:04 MST"), link),
+	})
+}
+
+// ForgotPassword mails a password reset link to the account of the email.
+// Requesting a new link invalidates the previous ones. Like
+// ResendVerification it is throttled and succeeds silently for unknown emails
+// and failed mails.
+func (a AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	allowed, err := a.mailThrottleService.Allow(ctx, "password_reset", req.Email, req.IpAddress)
+	if err != nil || !allowed {
+		return err
+	}
+
+	user, err := a.authRepository.FindUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		log.Println("Service Error (ForgotPassword):", err.Error())
+		return err
+	}
+
+	token, err := pkg.NewOpaqueToken(32)
+	if err != nil {
+		log.Println("Service Error (ForgotPassword):", err.Error())
+		return err
+	}
+	expiresAt := time.Now().Add(passwordResetTTL())
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (ForgotPassword):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := a.passwordResetRepository.ExpireUserPasswordResets(ctx, tx, user.Id); err != nil {
+		return err
+	}
+	err = a.passwordResetRepository.InsertPasswordReset(ctx, tx, model.PasswordReset{
+		UserId:    user.Id,
+		TokenHash: pkg.HashToken(token),
+		ExpiresAt: expiresAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (ForgotPassword):", err.Error())
+		return err
+	}
+
+	link := passwordResetURL() + "?token=" + url.QueryEscape(token)
+	err = a.mailer.Send(ctx, pkg.Mail{
+		To:      user.Email,
+		Subject: "Reset your Tickitz password",
+		Body: fmt.Sprintf("Hi,\n\nWe received a request to reset your password. Open the link below before %s to choose a new one:\n\n%s\n\nIf you did not ask for this, you can ignore this email; your password stays the same.\n",
+			expiresAt.Format("2006-01-02 15:04 MST"), link),
+	})
+	if err != nil {
+		log.Println("Service Error (ForgotPassword):", err.Error())
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. The
+// token is used up, and every session of the user is revoked.
+func (a AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	hc := pkg.HashConfig{}
+	hc.UseRecomended()
+
+	hp, err := hc.GenHash(req.NewPassword)
+	if err != nil {
+		log.Println("Service Error (ResetPassword):", err.Error())
+		return err
+	}
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (ResetPassword):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	reset, err := a.passwordResetRepository.GetPasswordResetForUpdate(ctx, tx, pkg.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrInvalidResetToken
+		}
+		return err
+	}
+	if reset.UsedAt != nil || !time.Now().Before(reset.ExpiresAt) {
+		return apperr.ErrInvalidResetToken
+	}
+
+	if err := a.passwordResetRepository.SetUserPassword(ctx, tx, reset.UserId, hp); err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return apperr.ErrInvalidResetToken
+		}
+		return err
+	}
+	if err := a.passwordResetRepository.ExpireUserPasswordResets(ctx, tx, reset.UserId); err != nil {
+		return err
+	}
+	if err := a.refreshTokenRepository.RevokeUserRefreshTokens(ctx, tx, reset.UserId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (ResetPassword):", err.Error())
+		return err
+	}
+
+	if err := a.sessionService.RevokeAllSessions(ctx, reset.UserId); err != nil {
+		log.Println("Service Error (ResetPassword):", err.Error())
+		return err
+	}
+	return nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Each refresh token can be exchanged once; presenting one that was
+// already exchanged means it has been copied, so the whole family, including
+// the token the legitimate client holds now, is revoked.
+func (a AuthService) Refresh(ctx context.Context, req dto.RefreshTokenRequest) (dto.LoginResponse, error) {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (Refresh):", err.Error())
+		return dto.LoginResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := a.refreshTokenRepository.GetRefreshTokenForUpdate(ctx, tx, pkg.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.LoginResponse{}, apperr.ErrInvalidRefreshToken
+		}
+		return dto.LoginResponse{}, err
+	}
+	if current.RevokedAt != nil {
+		return dto.LoginResponse{}, apperr.ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		log.Printf("Refresh token reuse detected for user %d, revoking family %s", current.UserId, current.FamilyId)
+		sessionId, err := a.sessionService.RevokeFamily(ctx, tx, current.FamilyId)
+		if err != nil {
+			return dto.LoginResponse{}, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Println("Service Error (Refresh):", err.Error())
+			return dto.LoginResponse{}, err
+		}
+		if err := a.sessionService.ClearSessionTokens(ctx, sessionId); err != nil {
+			return dto.LoginResponse{}, err
+		}
+		return dto.LoginResponse{}, apperr.ErrRefreshTokenReused
+	}
+	if !time.Now().Before(current.ExpiresAt) {
+		return dto.LoginResponse{}, apperr.ErrInvalidRefreshToken
+	}
+
+	user, err := a.authRepository.FindUserById(ctx, current.UserId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.LoginResponse{}, apperr.ErrInvalidRefreshToken
+		}
+		log.Println("Service Error (Refresh):", err.Error())
+		return dto.LoginResponse{}, err
+	}
+
+	if err := a.refreshTokenRepository.MarkRefreshTokenUsed(ctx, tx, current.Id); err != nil {
+		log.Println("Service Error (Refresh):", err.Error())
+		return dto.LoginResponse{}, err
+	}
+	refreshToken, expiresAt, err := a.newRefreshToken(ctx, tx, user.Id, current.FamilyId)
+	if err != nil {
+		log.Println("Service Error (Refresh):", err.Error())
+		return dto.LoginResponse{}, err
+	}
+	sessionId, err := a.sessionService.TouchSession(ctx, tx, current.FamilyId, req.IpAddress, expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.LoginResponse{}, apperr.ErrInvalidRefreshToken
+		}
+		log.Println("Service Error (Refresh):", err.Error())
+		return dto.LoginResponse{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (Refresh):", err.Error())
+		return dto.LoginResponse{}, err
+	}
+
+	return a.loginResponse(ctx, user, sessionId, refreshToken)
+}
+
+// newRefreshToken stores the hash of a new refresh token of the family and
+// returns the token itself along with its expiry.
+func (a AuthService) newRefreshToken(ctx context.Context, db repository.DBTX, userId int, familyId string) (string, time.Time, error) {
+	token, err := pkg.NewOpaqueToken(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().UTC().Add(pkg.RefreshTokenTTL())
+	err = a.refreshTokenRepository.InsertRefreshToken(ctx, db, model.RefreshToken{
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: pkg.HashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// loginResponse issues and whitelists an access token for a session of the
+// user.
+func (a AuthService) loginResponse(ctx context.Context, user model.User, sessionId int, refreshToken string) (dto.LoginResponse, error) {
+	jwtClaim := pkg.NewJWTClaim(user.Id, sessionId, user.Email, user.Role, user.EmailVerifiedAt != nil)
 	token, err := jwtClaim.GetToken()
 	if err != nil {
 		log.Println(err.Error())
 		return dto.LoginResponse{}, errors.New("internal server error")
 	}
 
-	err = a.authRepository.SaveToken(ctx, token, time.Hour)
+	err = a.authRepository.SaveToken(ctx, user.Id, sessionId, token, time.Hour)
 	if err != nil {
 		log.Println("Error save token: ", err.Error())
 		return dto.LoginResponse{}, errors.New("internal server error")
 	}
 
 	response := dto.LoginResponse{
-		Id:    user.Id,
-		Email: user.Email,
-		Role:  user.Role,
-		Token: token,
+		Id:           user.Id,
+		Email:        user.Email,
+		Role:         user.Role,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}
 
 	return response, nil
 }
 
-func (a AuthService) Logout(ctx context.Context, token string) error {
-	return a.authRepository.DeleteToken(ctx, token)
+// Logout revokes the session of the access token, which also revokes its
+// refresh tokens. A refresh token sent along is revoked as well, so tokens
+// issued before sessions were tracked can be logged out too.
+func (a AuthService) Logout(ctx context.Context, userId int, sessionId int, token string, req dto.LogoutRequest) error {
+	if sessionId != 0 {
+		err := a.sessionService.RevokeSession(ctx, userId, sessionId)
+		if err != nil && !errors.Is(err, apperr.ErrSessionNotFound) {
+			return err
+		}
+	}
+	if req.RefreshToken != "" {
+		if err := a.revokeRefreshToken(ctx, userId, req.RefreshToken); err != nil {
+			return err
+		}
+	}
+	return a.authRepository.DeleteToken(ctx, userId, token)
+}
+
+func (a AuthService) revokeRefreshToken(ctx context.Context, userId int, refreshToken string) error {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (Logout):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := a.refreshTokenRepository.GetRefreshTokenForUpdate(ctx, tx, pkg.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if current.UserId != userId {
+		return nil
+	}
+	revokedId, err := a.sessionService.RevokeFamily(ctx, tx, current.FamilyId)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return a.sessionService.ClearSessionTokens(ctx, revokedId)
 }
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+// CatalogService manages the directors, actors and genres movies refer to,
+// and the cast and genres of each movie.
+type CatalogService struct {
+	catalogRepository repository.CatalogRepo
+	db                *pgxpool.Pool
+}
+
+func NewCatalogService(catalogRepository repository.CatalogRepo, db *pgxpool.Pool) *CatalogService {
+	return &CatalogService{
+		catalogRepository: catalogRepository,
+		db:                db,
+	}
+}
+
+func toDirectorResponse(d model.Director) dto.DirectorResponse {
+	return dto.DirectorResponse{
+		Id:         d.Id,
+		Name:       d.Name,
+		MovieCount: d.MovieCount,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+func toActorResponse(a model.Actor) dto.ActorResponse {
+	return dto.ActorResponse{
+		Id:         a.Id,
+		Name:       a.Name,
+		MovieCount: a.MovieCount,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+func toGenreResponse(g model.Genre) dto.GenreResponse {
+	return dto.GenreResponse{
+		Id:         g.Id,
+		Name:       g.Name,
+		MovieCount: g.MovieCount,
+	}
+}
+
+// isPgError reports whether err is a Postgres error with the given code.
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+// optionalSearch trims search and returns nil when nothing is left.
+func optionalSearch(search string) *string {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return nil
+	}
+	return &search
+}
+
+func (s CatalogService) GetDirectors(ctx context.Context, search string) ([]dto.DirectorResponse, error) {
+	directors, err := s.catalogRepository.GetDirectors(ctx, s.db, optionalSearch(search))
+	if err != nil {
+		log.Println("Service Error (GetDirectors):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.DirectorResponse, 0, len(directors))
+	for _, director := range directors {
+		response = append(response, toDirectorResponse(director))
+	}
+	return response, nil
+}
+
+func (s CatalogService) GetDirector(ctx context.Context, id int) (dto.DirectorResponse, error) {
+	director, err := s.catalogRepository.GetDirectorById(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.DirectorResponse{}, apperr.ErrDirectorNotFound
+		}
+		log.Println("Service Error (GetDirector):", err.Error())
+		return dto.DirectorResponse{}, err
+	}
+	return toDirectorResponse(director), nil
+}
+
+func (s CatalogService) CreateDirector(ctx context.Context, req dto.PersonRequest) (dto.DirectorResponse, error) {
+	id, err := s.catalogRepository.InsertDirector(ctx, s.db, strings.TrimSpace(req.Name))
+	if err != nil {
+		log.Println("Service Error (CreateDirector):", err.Error())
+		return dto.DirectorResponse{}, err
+	}
+	return s.GetDirector(ctx, id)
+}
+
+func (s CatalogService) UpdateDirector(ctx context.Context, id int, req dto.PersonRequest) (dto.DirectorResponse, error) {
+	if err := s.catalogRepository.UpdateDirector(ctx, s.db, id, strings.TrimSpace(req.Name)); err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return dto.DirectorResponse{}, apperr.ErrDirectorNotFound
+		}
+		log.Println("Service Error (UpdateDirector):", err.Error())
+		return dto.DirectorResponse{}, err
+	}
+	return s.GetDirector(ctx, id)
+}
+
+// DeleteDirector deletes a director. Their movies are kept without a
+// director.
+func (s CatalogService) DeleteDirector(ctx context.Context, id int) error {
+	if err := s.catalogRepository.DeleteDirector(ctx, s.db, id); err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return apperr.ErrDirectorNotFound
+		}
+		log.Println("Service Error (DeleteDirector):", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s CatalogService) GetActors(ctx context.Context, search string) ([]dto.ActorResponse, error) {
+	actors, err := s.catalogRepository.GetActors(ctx, s.db, optionalSearch(search))
+	if err != nil {
+		log.Println("Service Error (GetActors):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.ActorResponse, 0, len(actors))
+	for _, actor := range actors {
+		response = append(response, toActorResponse(actor))
+	}
+	return response, nil
+}
+
+func (s CatalogService) GetActor(ctx context.Context, id int) (dto.ActorResponse, error) {
+	actor, err := s.catalogRepository.GetActorById(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ActorResponse{}, apperr.ErrActorNotFound
+		}
+		log.Println("Service Error (GetActor):", err.Error())
+		return dto.ActorResponse{}, err
+	}
+	return toActorResponse(actor), nil
+}
+
+func (s CatalogService) CreateActor(ctx context.Context, req dto.PersonRequest) (dto.ActorResponse, error) {
+	id, err := s.catalogRepository.InsertActor(ctx, s.db, strings.TrimSpace(req.Name))
+	if err != nil {
+		log.Println("Service Error (CreateActor):", err.Error())
+		return dto.ActorResponse{}, err
+	}
+	return s.GetActor(ctx, id)
+}
+
+func (s CatalogService) UpdateActor(ctx context.Context, id int, req dto.PersonRequest) (dto.ActorResponse, error) {
+	if err := s.catalogRepository.UpdateActor(ctx, s.db, id, strings.TrimSpace(req.Name)); err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return dto.ActorResponse{}, apperr.ErrActorNotFound
+		}
+		log.Println("Service Error (UpdateActor):", err.Error())
+		return dto.ActorResponse{}, err
+	}
+	return s.GetActor(ctx, id)
+}
+
+// DeleteActor deletes an actor and removes them from the cast of their movies.
+func (s CatalogService) DeleteActor(ctx context.Context, id int) error {
+	if err := s.catalogRepository.DeleteActor(ctx, s.db, id); err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return apperr.ErrActorNotFound
+		}
+		log.Println("Service Error (DeleteActor):", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s CatalogService) GetGenres(ctx context.Context) ([]dto.GenreResponse, error) {
+	genres, err := s.catalogRepository.GetGenres(ctx, s.db)
+	if err != nil {
+		log.Println("Service Error (GetGenres):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.GenreResponse, 0, len(genres))
+	for _, genre := range genres {
+		response = append(response, toGenreResponse(genre))
+	}
+	return response, nil
+}
+
+func (s CatalogService) GetGenre(ctx context.Context, id int) (dto.GenreResponse, error) {
+	genre, err := s.catalogRepository.GetGenreById(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.GenreResponse{}, apperr.ErrGenreNotFound
+		}
+		log.Println("Service Error (GetGenre):", err.Error())
+		return dto.GenreResponse{}, err
+	}
+	return toGenreResponse(genre), nil
+}
+
+func (s CatalogService) CreateGenre(ctx context.Context, req dto.GenreRequest) (dto.GenreResponse, error) {
+	id, err := s.catalogRepository.InsertGenre(ctx, s.db, strings.TrimSpace(req.Name))
+	if err != nil {
+		if isPgError(err, "23505") {
+			return dto.GenreResponse{}, apperr.ErrGenreNameTaken
+		}
+		log.Println("Service Error (CreateGenre):", err.Error())
+		return dto.GenreResponse{}, err
+	}
+	return s.GetGenre(ctx, id)
+}
+
+func (s CatalogService) UpdateGenre(ctx context.Context, id int, req dto.GenreRequest) (dto.GenreResponse, error) {
+	if err := s.catalogRepository.UpdateGenre(ctx, s.db, id, strings.TrimSpace(req.Name)); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNoRowsUpdated):
+			return dto.GenreResponse{}, apperr.ErrGenreNotFound
+		case isPgError(err, "23505"):
+			return dto.GenreResponse{}, apperr.ErrGenreNameTaken
+		}
+		log.Println("Service Error (UpdateGenre):", err.Error())
+		return dto.GenreResponse{}, err
+	}
+	return s.GetGenre(ctx, id)
+}
+
+// DeleteGenre deletes a genre and removes it from its movies.
+func (s CatalogService) DeleteGenre(ctx context.Context, id int) error {
+	if err := s.catalogRepository.DeleteGenre(ctx, s.db, id); err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return apperr.ErrGenreNotFound
+		}
+		log.Println("Service Error (DeleteGenre):", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s CatalogService) checkMovie(ctx context.Context, movieId int) error {
+	exists, err := s.catalogRepository.MovieExists(ctx, s.db, movieId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperr.ErrMovieNotFound
+	}
+	return nil
+}
+
+func (s CatalogService) GetMovieCast(ctx context.Context, movieId int) ([]dto.MovieCastResponse, error) {
+	if err := s.checkMovie(ctx, movieId); err != nil {
+		return nil, err
+	}
+	return s.movieCast(ctx, s.db, movieId)
+}
+
+// SaveMovieCast replaces the cast of a movie. Every actor may appear once and
+// every billing order may be used once.
+func (s CatalogService) SaveMovieCast(ctx context.Context, movieId int, req dto.MovieCastRequest) ([]dto.MovieCastResponse, error) {
+	cast := make([]model.MovieCast, 0, len(req.Cast))
+	actors := make(map[int]bool, len(req.Cast))
+	billing := make(map[int]bool, len(req.Cast))
+	for _, member := range req.Cast {
+		if actors[member.ActorId] {
+			return nil, fmt.Errorf("%w: actor %d is listed more than once", apperr.ErrInvalidCast, member.ActorId)
+		}
+		actors[member.ActorId] = true
+		if member.BillingOrder != nil {
+			if billing[*member.BillingOrder] {
+				return nil, fmt.Errorf("%w: billing order %d is used more than once", apperr.ErrInvalidCast, *member.BillingOrder)
+			}
+			billing[*member.BillingOrder] = true
+		}
+
+		cast = append(cast, model.MovieCast{
+			MovieId:       movieId,
+			ActorId:       member.ActorId,
+			CharacterName: strings.TrimSpace(member.CharacterName),
+			BillingOrder:  member.BillingOrder,
+		})
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (SaveMovieCast):", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.catalogRepository.LockMovie(ctx, tx, movieId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrMovieNotFound
+		}
+		return nil, err
+	}
+	if err := s.catalogRepository.ReplaceMovieCast(ctx, tx, movieId, cast); err != nil {
+		if isPgError(err, "23503") {
+			return nil, apperr.ErrActorNotFound
+		}
+		log.Println("Service Error (SaveMovieCast):", err.Error())
+		return nil, err
+	}
+
+	response, err := s.movieCast(ctx, tx, movieId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (SaveMovieCast):", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s CatalogService) movieCast(ctx context.Context, db repository.DBTX, movieId int) ([]dto.MovieCastResponse, error) {
+	cast, err := s.catalogRepository.GetMovieCast(ctx, db, movieId)
+	if err != nil {
+		log.Println("Service Error (GetMovieCast):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.MovieCastResponse, 0, len(cast))
+	for _, member := range cast {
+		response = append(response, dto.MovieCastResponse{
+			ActorId:       member.ActorId,
+			ActorName:     member.ActorName,
+			CharacterName: member.CharacterName,
+			BillingOrder:  member.BillingOrder,
+		})
+	}
+	return response, nil
+}
+
+func (s CatalogService) GetMovieGenres(ctx context.Context, movieId int) ([]dto.GenreResponse, error) {
+	if err := s.checkMovie(ctx, movieId); err != nil {
+		return nil, err
+	}
+	return s.movieGenres(ctx, s.db, movieId)
+}
+
+// SaveMovieGenres replaces the genres of a movie. Repeated ids are ignored.
+func (s CatalogService) SaveMovieGenres(ctx context.Context, movieId int, req dto.MovieGenresRequest) ([]dto.GenreResponse, error) {
+	genreIds := slices.Clone(req.GenreIds)
+	slices.Sort(genreIds)
+	genreIds = slices.Compact(genreIds)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (SaveMovieGenres):", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.catalogRepository.LockMovie(ctx, tx, movieId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrMovieNotFound
+		}
+		return nil, err
+	}
+	if err := s.catalogRepository.ReplaceMovieGenres(ctx, tx, movieId, genreIds); err != nil {
+		if isPgError(err, "23503") {
+			return nil, apperr.ErrGenreNotFound
+		}
+		log.Println("Service Error (SaveMovieGenres):", err.Error())
+		return nil, err
+	}
+
+	response, err := s.movieGenres(ctx, tx, movieId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (SaveMovieGenres):", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s CatalogService) movieGenres(ctx context.Context, db repository.DBTX, movieId int) ([]dto.GenreResponse, error) {
+	genres, err := s.catalogRepository.GetMovieGenres(ctx, db, movieId)
+	if err != nil {
+		log.Println("Service Error (GetMovieGenres):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.GenreResponse, 0, len(genres))
+	for _, genre := range genres {
+		response = append(response, toGenreResponse(genre))
+	}
+	return response, nil
+}
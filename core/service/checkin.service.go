@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+type CheckinService struct {
+	orderRepository repository.OrderRepo
+	db              *pgxpool.Pool
+}
+
+func NewCheckinService(orderRepository repository.OrderRepo, db *pgxpool.Pool) *CheckinService {
+	return &CheckinService{
+		orderRepository: orderRepository,
+		db:              db,
+	}
+}
+
+// Checkin admits the seats of a paid ticket at the gate. The code is either
+// the signed QR payload or the plain booking code. Without seat ids every
+// seat that was not admitted yet is admitted; a ticket whose seats were all
+// used already is rejected.
+func (s CheckinService) Checkin(ctx context.Context, staffId int, req dto.CheckinRequest) (dto.CheckinResponse, error) {
+	code := strings.TrimSpace(req.Code)
+
+	var orderId int
+	var bookingCode string
+	if strings.HasPrefix(code, "TKZ1.") {
+		ticket, err := pkg.ParseTicket(code)
+		if err != nil {
+			if errors.Is(err, pkg.ErrNoTicketSecret) {
+				log.Println("Service Error (Checkin):", err.Error())
+			}
+			return dto.CheckinResponse{}, err
+		}
+		orderId = ticket.OrderId
+		bookingCode = ticket.BookingCode
+	} else {
+		id, err := s.orderRepository.GetOrderIdByBookingCode(ctx, s.db, code)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return dto.CheckinResponse{}, apperr.ErrOrderNotFound
+			}
+			return dto.CheckinResponse{}, err
+		}
+		orderId = id
+		bookingCode = code
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return dto.CheckinResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := s.orderRepository.GetOrderByIdForUpdate(ctx, tx, orderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CheckinResponse{}, apperr.ErrOrderNotFound
+		}
+		return dto.CheckinResponse{}, err
+	}
+	if order.BookingCode != bookingCode {
+		return dto.CheckinResponse{}, pkg.ErrInvalidTicket
+	}
+	if order.ScheduleId != req.ScheduleId {
+		return dto.CheckinResponse{}, apperr.ErrTicketWrongSchedule
+	}
+	if order.PaymentStatus != model.OrderStatusPaid {
+		return dto.CheckinResponse{}, apperr.ErrOrderNotPaid
+	}
+
+	seats, err := s.orderRepository.GetSeatAdmissions(ctx, tx, orderId)
+	if err != nil {
+		return dto.CheckinResponse{}, err
+	}
+
+	seatIds, err := seatsToAdmit(seats, req.SeatIds)
+	if err != nil {
+		return dto.CheckinResponse{}, err
+	}
+
+	admitted, err := s.orderRepository.AdmitSeats(ctx, tx, orderId, seatIds, staffId)
+	if err != nil {
+		log.Println("Service Error (Checkin):", err.Error())
+		return dto.CheckinResponse{}, err
+	}
+
+	seats, err = s.orderRepository.GetSeatAdmissions(ctx, tx, orderId)
+	if err != nil {
+		return dto.CheckinResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (Checkin):", err.Error())
+		return dto.CheckinResponse{}, err
+	}
+
+	response := dto.CheckinResponse{
+		OrderId:     order.Id,
+		BookingCode: order.BookingCode,
+		ScheduleId:  order.ScheduleId,
+		Admitted:    admitted,
+		Seats:       make([]dto.CheckinSeat, 0, len(seats)),
+	}
+	for _, seat := range seats {
+		response.Seats = append(response.Seats, dto.CheckinSeat{
+			SeatId:     seat.SeatId,
+			RowLetter:  seat.RowLetter,
+			SeatNumber: seat.SeatNumber,
+			SeatType:   seat.SeatType,
+			AdmittedAt: seat.AdmittedAt,
+		})
+	}
+	return response, nil
+}
+
+// seatsToAdmit picks the seats to admit from the order's seats. Requested
+// seats must belong to the order and must not have been admitted before.
+func seatsToAdmit(seats []model.SeatAdmission, requested []int) ([]int, error) {
+	admittedAt := make(map[int]*time.Time, len(seats))
+	for _, seat := range seats {
+		admittedAt[seat.SeatId] = seat.AdmittedAt
+	}
+
+	if len(requested) == 0 {
+		for _, seat := range seats {
+			requested = append(requested, seat.SeatId)
+		}
+		var pending []int
+		for _, seatId := range requested {
+			if admittedAt[seatId] == nil {
+				pending = append(pending, seatId)
+			}
+		}
+		if len(pending) == 0 {
+			return nil, ticketUsed(requested, admittedAt)
+		}
+		return pending, nil
+	}
+
+	var unknown, used []int
+	for _, seatId := range requested {
+		at, ok := admittedAt[seatId]
+		switch {
+		case !ok:
+			unknown = append(unknown, seatId)
+		case at != nil:
+			used = append(used, seatId)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, &apperr.InvalidSeatsError{Reason: "seats are not part of this ticket", SeatIds: unknown}
+	}
+	if len(used) > 0 {
+		return nil, ticketUsed(used, admittedAt)
+	}
+	return slices.Compact(slices.Sorted(slices.Values(requested))), nil
+}
+
+func ticketUsed(seatIds []int, admittedAt map[int]*time.Time) error {
+	var last time.Time
+	for _, seatId := range seatIds {
+		if at := admittedAt[seatId]; at != nil && at.After(last) {
+			last = *at
+		}
+	}
+	return &apperr.TicketUsedError{SeatIds: seatIds, AdmittedAt: last}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+func TestSeatsToAdmit(t *testing.T) {
+	earlier := time.Date(2026, 1, 2, 19, 0, 0, 0, time.UTC)
+	later := earlier.Add(5 * time.Minute)
+	seats := []model.SeatAdmission{
+		{SeatId: 1},
+		{SeatId: 2, AdmittedAt: &earlier},
+		{SeatId: 3},
+	}
+	allUsed := []model.SeatAdmission{
+		{SeatId: 1, AdmittedAt: &earlier},
+		{SeatId: 2, AdmittedAt: &later},
+	}
+
+	tests := []struct {
+		name      string
+		seats     []model.SeatAdmission
+		requested []int
+		want      []int
+		unknown   []int
+		used      []int
+		usedAt    time.Time
+	}{
+		{name: "all pending seats", seats: seats, want: []int{1, 3}},
+		{name: "requested seats sorted", seats: seats, requested: []int{3, 1}, want: []int{1, 3}},
+		{name: "duplicates admitted once", seats: seats, requested: []int{3, 3}, want: []int{3}},
+		{name: "seat of another ticket", seats: seats, requested: []int{1, 9}, unknown: []int{9}},
+		{name: "seat already admitted", seats: seats, requested: []int{1, 2}, used: []int{2}, usedAt: earlier},
+		{name: "whole ticket used", seats: allUsed, used: []int{1, 2}, usedAt: later},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := seatsToAdmit(tt.seats, tt.requested)
+
+			var invalidSeats *apperr.InvalidSeatsError
+			var ticketUsed *apperr.TicketUsedError
+			switch {
+			case tt.unknown != nil:
+				if !errors.As(err, &invalidSeats) || !slices.Equal(invalidSeats.SeatIds, tt.unknown) {
+					t.Fatalf("seatsToAdmit() error = %v, want unknown seats %v", err, tt.unknown)
+				}
+			case tt.used != nil:
+				if !errors.As(err, &ticketUsed) || !slices.Equal(ticketUsed.SeatIds, tt.used) {
+					t.Fatalf("seatsToAdmit() error = %v, want used seats %v", err, tt.used)
+				}
+				if !ticketUsed.AdmittedAt.Equal(tt.usedAt) {
+					t.Errorf("AdmittedAt = %v, want %v", ticketUsed.AdmittedAt, tt.usedAt)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("seatsToAdmit() error = %v", err)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("seatsToAdmit() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
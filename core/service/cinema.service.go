@@ -0,0 +1,505 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+const defaultSeatType = "regular"
+
+type CinemaService struct {
+	cinemaRepository repository.CinemaRepo
+	db               *pgxpool.Pool
+}
+
+func NewCinemaService(cinemaRepository repository.CinemaRepo, db *pgxpool.Pool) *CinemaService {
+	return &CinemaService{
+		cinemaRepository: cinemaRepository,
+		db:               db,
+	}
+}
+
+func toCityResponse(c model.City) dto.CityResponse {
+	return dto.CityResponse{
+		Id:   c.Id,
+		Name: c.Name,
+	}
+}
+
+func toCinemaResponse(c model.Cinema) dto.CinemaResponse {
+	return dto.CinemaResponse{
+		Id:        c.Id,
+		Name:      c.Name,
+		LogoUrl:   c.LogoUrl,
+		Location:  c.Location,
+		CityId:    c.CityId,
+		CityName:  c.CityName,
+		Studios:   c.Studios,
+		SeatCount: c.SeatCount,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func toAuditoriumResponse(a model.Auditorium) dto.AuditoriumResponse {
+	return dto.AuditoriumResponse{
+		Id:         a.Id,
+		CinemaId:   a.CinemaId,
+		CinemaName: a.CinemaName,
+		Name:       a.Name,
+		SeatCount:  a.SeatCount,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+// cinemaWriteError turns constraint violations into errors the admin can act
+// on.
+func cinemaWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return apperr.ErrCityNameTaken
+		case "23503":
+			return apperr.ErrCityNotFound
+		}
+	}
+	return err
+}
+
+// auditoriumWriteError is cinemaWriteError for auditoriums.
+func auditoriumWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return apperr.ErrAuditoriumNameTaken
+		case "23503":
+			return apperr.ErrCinemaNotFound
+		}
+	}
+	return err
+}
+
+func (s CinemaService) GetCities(ctx context.Context) ([]dto.CityResponse, error) {
+	cities, err := s.cinemaRepository.GetCities(ctx, s.db)
+	if err != nil {
+		log.Println("Service Error (GetCities):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.CityResponse, 0, len(cities))
+	for _, city := range cities {
+		response = append(response, toCityResponse(city))
+	}
+	return response, nil
+}
+
+func (s CinemaService) CreateCity(ctx context.Context, req dto.CityRequest) (dto.CityResponse, error) {
+	city, err := s.cinemaRepository.InsertCity(ctx, s.db, strings.TrimSpace(req.Name))
+	if err != nil {
+		log.Println("Service Error (CreateCity):", err.Error())
+		return dto.CityResponse{}, cinemaWriteError(err)
+	}
+	return toCityResponse(city), nil
+}
+
+func (s CinemaService) UpdateCity(ctx context.Context, id int, req dto.CityRequest) (dto.CityResponse, error) {
+	city, err := s.cinemaRepository.UpdateCity(ctx, s.db, id, strings.TrimSpace(req.Name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CityResponse{}, apperr.ErrCityNotFound
+		}
+		log.Println("Service Error (UpdateCity):", err.Error())
+		return dto.CityResponse{}, cinemaWriteError(err)
+	}
+	return toCityResponse(city), nil
+}
+
+// DeleteCity deletes a city without cinemas. Deleting a city would cascade
+// to its cinemas, schedules and orders, so those must be removed first.
+func (s CinemaService) DeleteCity(ctx context.Context, id int) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (DeleteCity):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := s.cinemaRepository.GetCityForUpdate(ctx, tx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrCityNotFound
+		}
+		return err
+	}
+
+	count, err := s.cinemaRepository.CountCinemasInCity(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return apperr.ErrCityInUse
+	}
+
+	if err := s.cinemaRepository.DeleteCity(ctx, tx, id); err != nil {
+		log.Println("Service Error (DeleteCity):", err.Error())
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s CinemaService) GetCinemas(ctx context.Context, cityId *int) ([]dto.CinemaResponse, error) {
+	cinemas, err := s.cinemaRepository.GetCinemas(ctx, s.db, cityId)
+	if err != nil {
+		log.Println("Service Error (GetCinemas):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.CinemaResponse, 0, len(cinemas))
+	for _, cinema := range cinemas {
+		response = append(response, toCinemaResponse(cinema))
+	}
+	return response, nil
+}
+
+func (s CinemaService) GetCinema(ctx context.Context, id int) (dto.CinemaResponse, error) {
+	cinema, err := s.cinemaRepository.GetCinemaById(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CinemaResponse{}, apperr.ErrCinemaNotFound
+		}
+		log.Println("Service Error (GetCinema):", err.Error())
+		return dto.CinemaResponse{}, err
+	}
+	return toCinemaResponse(cinema), nil
+}
+
+func (s CinemaService) CreateCinema(ctx context.Context, req dto.CreateCinemaRequest) (dto.CinemaResponse, error) {
+	id, err := s.cinemaRepository.InsertCinema(ctx, s.db, req)
+	if err != nil {
+		log.Println("Service Error (CreateCinema):", err.Error())
+		return dto.CinemaResponse{}, cinemaWriteError(err)
+	}
+	return s.GetCinema(ctx, id)
+}
+
+func (s CinemaService) UpdateCinema(ctx context.Context, id int, req dto.UpdateCinemaRequest) (dto.CinemaResponse, error) {
+	err := s.cinemaRepository.UpdateCinema(ctx, s.db, id, req)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return dto.CinemaResponse{}, apperr.ErrCinemaNotFound
+		}
+		log.Println("Service Error (UpdateCinema):", err.Error())
+		return dto.CinemaResponse{}, cinemaWriteError(err)
+	}
+	return s.GetCinema(ctx, id)
+}
+
+// DeleteCinema deletes a cinema without schedules, along with its
+// auditoriums and seats. Cinemas with schedules would take their orders with
+// them.
+func (s CinemaService) DeleteCinema(ctx context.Context, id int) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (DeleteCinema):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.cinemaRepository.LockCinema(ctx, tx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrCinemaNotFound
+		}
+		return err
+	}
+
+	count, err := s.cinemaRepository.CountCinemaSchedules(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return apperr.ErrCinemaInUse
+	}
+
+	if err := s.cinemaRepository.DeleteCinema(ctx, tx, id); err != nil {
+		log.Println("Service Error (DeleteCinema):", err.Error())
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s CinemaService) GetAuditoriums(ctx context.Context, cinemaId int) ([]dto.AuditoriumResponse, error) {
+	if _, err := s.cinemaRepository.GetCinemaById(ctx, s.db, cinemaId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrCinemaNotFound
+		}
+		return nil, err
+	}
+
+	auditoriums, err := s.cinemaRepository.GetAuditoriums(ctx, s.db, cinemaId)
+	if err != nil {
+		log.Println("Service Error (GetAuditoriums):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.AuditoriumResponse, 0, len(auditoriums))
+	for _, auditorium := range auditoriums {
+		response = append(response, toAuditoriumResponse(auditorium))
+	}
+	return response, nil
+}
+
+func (s CinemaService) GetAuditorium(ctx context.Context, id int) (dto.AuditoriumResponse, error) {
+	auditorium, err := s.cinemaRepository.GetAuditoriumById(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.AuditoriumResponse{}, apperr.ErrAuditoriumNotFound
+		}
+		log.Println("Service Error (GetAuditorium):", err.Error())
+		return dto.AuditoriumResponse{}, err
+	}
+	return toAuditoriumResponse(auditorium), nil
+}
+
+func (s CinemaService) CreateAuditorium(ctx context.Context, cinemaId int, req dto.AuditoriumRequest) (dto.AuditoriumResponse, error) {
+	id, err := s.cinemaRepository.InsertAuditorium(ctx, s.db, cinemaId, strings.TrimSpace(req.Name))
+	if err != nil {
+		log.Println("Service Error (CreateAuditorium):", err.Error())
+		return dto.AuditoriumResponse{}, auditoriumWriteError(err)
+	}
+	return s.GetAuditorium(ctx, id)
+}
+
+func (s CinemaService) UpdateAuditorium(ctx context.Context, id int, req dto.AuditoriumRequest) (dto.AuditoriumResponse, error) {
+	err := s.cinemaRepository.UpdateAuditorium(ctx, s.db, id, strings.TrimSpace(req.Name))
+	if err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return dto.AuditoriumResponse{}, apperr.ErrAuditoriumNotFound
+		}
+		log.Println("Service Error (UpdateAuditorium):", err.Error())
+		return dto.AuditoriumResponse{}, auditoriumWriteError(err)
+	}
+	return s.GetAuditorium(ctx, id)
+}
+
+// DeleteAuditorium deletes an auditorium without schedules, along with its
+// seats.
+func (s CinemaService) DeleteAuditorium(ctx context.Context, id int) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (DeleteAuditorium):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := s.cinemaRepository.GetAuditoriumForUpdate(ctx, tx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrAuditoriumNotFound
+		}
+		return err
+	}
+
+	count, err := s.cinemaRepository.CountAuditoriumSchedules(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return apperr.ErrAuditoriumInUse
+	}
+
+	if err := s.cinemaRepository.DeleteAuditorium(ctx, tx, id); err != nil {
+		log.Println("Service Error (DeleteAuditorium):", err.Error())
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s CinemaService) GetSeatLayout(ctx context.Context, auditoriumId int) (dto.SeatLayoutResponse, error) {
+	auditorium, err := s.cinemaRepository.GetAuditoriumById(ctx, s.db, auditoriumId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.SeatLayoutResponse{}, apperr.ErrAuditoriumNotFound
+		}
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	seats, err := s.cinemaRepository.GetAuditoriumSeats(ctx, s.db, auditoriumId)
+	if err != nil {
+		log.Println("Service Error (GetSeatLayout):", err.Error())
+		return dto.SeatLayoutResponse{}, err
+	}
+	return toSeatLayoutResponse(auditorium, seats), nil
+}
+
+// SaveSeatLayout replaces the seat grid of the auditorium with the one
+// described by req. Seats that stay at the same row and number keep their
+// id, so existing orders and holds are not affected. Seats that would be
+// removed but already appear in an order make the whole layout fail.
+func (s CinemaService) SaveSeatLayout(ctx context.Context, auditoriumId int, req dto.SeatLayoutRequest) (dto.SeatLayoutResponse, error) {
+	layout, err := buildSeatLayout(req)
+	if err != nil {
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (SaveSeatLayout):", err.Error())
+		return dto.SeatLayoutResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	auditorium, err := s.cinemaRepository.GetAuditoriumForUpdate(ctx, tx, auditoriumId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.SeatLayoutResponse{}, apperr.ErrAuditoriumNotFound
+		}
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	current, err := s.cinemaRepository.GetAuditoriumSeats(ctx, tx, auditoriumId)
+	if err != nil {
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	keep := make(map[string]bool, len(layout))
+	for _, seat := range layout {
+		keep[seatLabel(seat)] = true
+	}
+	var removed []int
+	labels := make(map[int]string)
+	for _, seat := range current {
+		if !keep[seatLabel(seat)] {
+			removed = append(removed, seat.SeatId)
+			labels[seat.SeatId] = seatLabel(seat)
+		}
+	}
+
+	if len(removed) > 0 {
+		ordered, err := s.cinemaRepository.GetOrderedSeatIds(ctx, tx, removed)
+		if err != nil {
+			return dto.SeatLayoutResponse{}, err
+		}
+		if len(ordered) > 0 {
+			names := make([]string, 0, len(ordered))
+			for _, id := range ordered {
+				names = append(names, labels[id])
+			}
+			return dto.SeatLayoutResponse{}, fmt.Errorf("%w: %s", apperr.ErrSeatsBooked, strings.Join(names, ", "))
+		}
+		if err := s.cinemaRepository.DeleteSeats(ctx, tx, removed); err != nil {
+			log.Println("Service Error (SaveSeatLayout):", err.Error())
+			return dto.SeatLayoutResponse{}, err
+		}
+	}
+
+	if err := s.cinemaRepository.UpsertSeats(ctx, tx, auditorium, layout); err != nil {
+		log.Println("Service Error (SaveSeatLayout):", err.Error())
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	seats, err := s.cinemaRepository.GetAuditoriumSeats(ctx, tx, auditoriumId)
+	if err != nil {
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (SaveSeatLayout):", err.Error())
+		return dto.SeatLayoutResponse{}, err
+	}
+	return toSeatLayoutResponse(auditorium, seats), nil
+}
+
+// buildSeatLayout expands the rows of req into the seats to create.
+func buildSeatLayout(req dto.SeatLayoutRequest) ([]model.Seat, error) {
+	var seats []model.Seat
+	rows := make(map[string]bool, len(req.Rows))
+	for _, row := range req.Rows {
+		letter := strings.ToUpper(row.Row)
+		if rows[letter] {
+			return nil, fmt.Errorf("%w: row %s is defined twice", apperr.ErrInvalidSeatLayout, letter)
+		}
+		rows[letter] = true
+
+		types := make([]string, row.Seats+1)
+		for n := 1; n <= row.Seats; n++ {
+			types[n] = normalizeSeatType(row.SeatType)
+		}
+		for _, section := range row.Sections {
+			if section.From > section.To || section.To > row.Seats {
+				return nil, fmt.Errorf("%w: section %d-%d is outside row %s", apperr.ErrInvalidSeatLayout, section.From, section.To, letter)
+			}
+			for n := section.From; n <= section.To; n++ {
+				types[n] = normalizeSeatType(section.SeatType)
+			}
+		}
+		for _, gap := range row.Gaps {
+			if gap > row.Seats {
+				return nil, fmt.Errorf("%w: gap %d is outside row %s", apperr.ErrInvalidSeatLayout, gap, letter)
+			}
+		}
+
+		count := 0
+		for n := 1; n <= row.Seats; n++ {
+			if slices.Contains(row.Gaps, n) {
+				continue
+			}
+			seats = append(seats, model.Seat{
+				RowLetter:  letter,
+				SeatNumber: n,
+				SeatType:   types[n],
+			})
+			count++
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: row %s has no seats", apperr.ErrInvalidSeatLayout, letter)
+		}
+	}
+	return seats, nil
+}
+
+func normalizeSeatType(seatType string) string {
+	seatType = strings.ToLower(strings.TrimSpace(seatType))
+	if seatType == "" {
+		return defaultSeatType
+	}
+	return seatType
+}
+
+func seatLabel(seat model.Seat) string {
+	return fmt.Sprintf("%s%d", seat.RowLetter, seat.SeatNumber)
+}
+
+// toSeatLayoutResponse groups seats, ordered by row and number, into rows.
+func toSeatLayoutResponse(auditorium model.Auditorium, seats []model.Seat) dto.SeatLayoutResponse {
+	response := dto.SeatLayoutResponse{
+		AuditoriumId: auditorium.Id,
+		CinemaId:     auditorium.CinemaId,
+		TotalSeats:   len(seats),
+		Rows:         []dto.SeatLayoutRow{},
+	}
+	for _, seat := range seats {
+		last := len(response.Rows) - 1
+		if last < 0 || response.Rows[last].Row != seat.RowLetter {
+			response.Rows = append(response.Rows, dto.SeatLayoutRow{Row: seat.RowLetter})
+			last++
+		}
+		response.Rows[last].Seats = append(response.Rows[last].Seats, dto.SeatLayoutSeat{
+			SeatId:     seat.SeatId,
+			SeatNumber: seat.SeatNumber,
+			SeatType:   seat.SeatType,
+		})
+	}
+	return response
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+// loginLimit is how many failed logins a scope gets for free before each
+// further failure backs it off exponentially, and after how many it is
+// locked out.
+type loginLimit struct {
+	scope string
+	free  int
+	max   int
+}
+
+func emailLoginLimit() loginLimit {
+	return loginLimit{
+		scope: "email",
+		free:  pkg.GetEnvInt("LOGIN_BACKOFF_AFTER", 3),
+		max:   pkg.GetEnvInt("LOGIN_MAX_FAILURES", 10),
+	}
+}
+
+// ipLoginLimit is looser than emailLoginLimit, since many users can share an
+// address behind NAT.
+func ipLoginLimit() loginLimit {
+	return loginLimit{
+		scope: "ip",
+		free:  pkg.GetEnvInt("LOGIN_IP_BACKOFF_AFTER", 10),
+		max:   pkg.GetEnvInt("LOGIN_IP_MAX_FAILURES", 50),
+	}
+}
+
+// loginBackoffBase is the delay after the first failure past the free ones;
+// it doubles with every further failure.
+func loginBackoffBase() time.Duration {
+	return pkg.GetEnvDuration("LOGIN_BACKOFF_BASE", time.Second)
+}
+
+func loginLockoutDuration() time.Duration {
+	return pkg.GetEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+}
+
+// loginFailureWindow is how long failures are remembered after the last one.
+func loginFailureWindow() time.Duration {
+	return pkg.GetEnvDuration("LOGIN_FAILURE_WINDOW", time.Hour)
+}
+
+// delay returns how long the scope is locked after its n-th failure.
+func (l loginLimit) delay(n int) time.Duration {
+	lockout := loginLockoutDuration()
+	if n >= l.max {
+		return lockout
+	}
+	if n <= l.free {
+		return 0
+	}
+	d := loginBackoffBase()
+	for i := l.free + 1; i < n && d < lockout; i++ {
+		d *= 2
+	}
+	return min(d, lockout)
+}
+
+type LoginThrottleService struct {
+	loginAttemptRepository *repository.LoginAttemptRepository
+}
+
+func NewLoginThrottleService(loginAttemptRepository *repository.LoginAttemptRepository) *LoginThrottleService {
+	return &LoginThrottleService{
+		loginAttemptRepository: loginAttemptRepository,
+	}
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Check returns a *apperr.LoginThrottledError while the email or the IP
+// address is locked.
+func (l LoginThrottleService) Check(ctx context.Context, email string, ipAddress string) error {
+	var retryAfter time.Duration
+	for _, limit := range []struct{ scope, id string }{
+		{"email", normalizeLoginEmail(email)},
+		{"ip", ipAddress},
+	} {
+		if limit.id == "" {
+			continue
+		}
+		ttl, err := l.loginAttemptRepository.GetLock(ctx, limit.scope, limit.id)
+		if err != nil {
+			log.Println("Service Error (CheckLogin):", err.Error())
+			return err
+		}
+		retryAfter = max(retryAfter, ttl)
+	}
+	if retryAfter > 0 {
+		return &apperr.LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login for the email and the IP address and
+// locks them for as long as their limits say.
+func (l LoginThrottleService) RecordFailure(ctx context.Context, email string, ipAddress string) error {
+	for _, limit := range []struct {
+		loginLimit
+		id string
+	}{
+		{emailLoginLimit(), normalizeLoginEmail(email)},
+		{ipLoginLimit(), ipAddress},
+	} {
+		if limit.id == "" {
+			continue
+		}
+		failures, err := l.loginAttemptRepository.RecordFailure(ctx, limit.scope, limit.id, loginFailureWindow())
+		if err != nil {
+			log.Println("Service Error (RecordLoginFailure):", err.Error())
+			return err
+		}
+		d := limit.delay(failures)
+		if d == 0 {
+			continue
+		}
+		if failures >= limit.max {
+			log.Printf("Login locked out for %s %s after %d failures", limit.scope, limit.id, failures)
+		}
+		if err := l.loginAttemptRepository.Lock(ctx, limit.scope, limit.id, d); err != nil {
+			log.Println("Service Error (RecordLoginFailure):", err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordSuccess forgets the failures of the email. Failures of the IP address
+// are kept, so logging in to one's own account does not reset the count of
+// guesses made at others.
+func (l LoginThrottleService) RecordSuccess(ctx context.Context, email string) error {
+	if err := l.loginAttemptRepository.Clear(ctx, "email", normalizeLoginEmail(email)); err != nil {
+		log.Println("Service Error (RecordLoginSuccess):", err.Error())
+		return err
+	}
+	return nil
+}
+
+// Unlock lifts the lock of an email, an IP address or both, and forgets their
+// failures.
+func (l LoginThrottleService) Unlock(ctx context.Context, req dto.UnlockLoginRequest) error {
+	if req.Email == "" && req.IpAddress == "" {
+		return fmt.Errorf("%w: email or ip_address is required", apperr.ErrInvalidUnlockRequest)
+	}
+	if req.Email != "" {
+		if err := l.loginAttemptRepository.Clear(ctx, "email", normalizeLoginEmail(req.Email)); err != nil {
+			log.Println("Service Error (UnlockLogin):", err.Error())
+			return err
+		}
+	}
+	if req.IpAddress != "" {
+		if err := l.loginAttemptRepository.Clear(ctx, "ip", req.IpAddress); err != nil {
+			log.Println("Service Error (UnlockLogin):", err.Error())
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLimitDelay(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_BASE", "1s")
+	t.Setenv("LOGIN_LOCKOUT_DURATION", "15m")
+	limit := loginLimit{scope: "email", free: 3, max: 10}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 4 * time.Second},
+		{9, 32 * time.Second},
+		{10, 15 * time.Minute},
+		{25, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := limit.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginLimitDelayCappedAtLockout(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_BASE", "1m")
+	t.Setenv("LOGIN_LOCKOUT_DURATION", "5m")
+	limit := loginLimit{scope: "ip", free: 0, max: 100}
+
+	for failures := 1; failures < limit.max; failures++ {
+		if got := limit.delay(failures); got > 5*time.Minute {
+			t.Fatalf("delay(%d) = %v, longer than the lockout", failures, got)
+		}
+	}
+	if got := limit.delay(4); got != 5*time.Minute {
+		t.Errorf("delay(4) = %v, want %v", got, 5*time.Minute)
+	}
+}
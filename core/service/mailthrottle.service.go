@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+// mailCooldown is how long after a mail to an email another one of the same
+// kind is not sent.
+func mailCooldown() time.Duration {
+	return pkg.GetEnvDuration("MAIL_COOLDOWN", time.Minute)
+}
+
+func mailIpMaxRequests() int {
+	return pkg.GetEnvInt("MAIL_IP_MAX_REQUESTS", 10)
+}
+
+func mailIpWindow() time.Duration {
+	return pkg.GetEnvDuration("MAIL_IP_WINDOW", time.Hour)
+}
+
+type MailThrottleService struct {
+	mailThrottleRepository *repository.MailThrottleRepository
+}
+
+func NewMailThrottleService(mailThrottleRepository *repository.MailThrottleRepository) *MailThrottleService {
+	return &MailThrottleService{
+		mailThrottleRepository: mailThrottleRepository,
+	}
+}
+
+// Allow is asked before mail of kind is sent to the email on a request from
+// the IP address. Past MAIL_IP_MAX_REQUESTS per MAIL_IP_WINDOW the address
+// gets a *apperr.RequestThrottledError. Within MAIL_COOLDOWN of the previous
+// mail to the email it returns false, and the caller answers as if the mail
+// was sent: the cooldown runs for unknown emails too, so it does not tell
+// which emails have an account.
+func (m MailThrottleService) Allow(ctx context.Context, kind string, email string, ipAddress string) (bool, error) {
+	if ipAddress != "" {
+		count, retryAfter, err := m.mailThrottleRepository.RecordRequest(ctx, kind, ipAddress, mailIpWindow())
+		if err != nil {
+			log.Println("Service Error (AllowMail):", err.Error())
+			return false, err
+		}
+		if count > mailIpMaxRequests() {
+			return false, &apperr.RequestThrottledError{RetryAfter: retryAfter}
+		}
+	}
+
+	allowed, err := m.mailThrottleRepository.StartCooldown(ctx, kind, normalizeLoginEmail(email), mailCooldown())
+	if err != nil {
+		log.Println("Service Error (AllowMail):", err.Error())
+		return false, err
+	}
+	return allowed, nil
+}
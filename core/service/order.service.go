@@ -2,28 +2,67 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
 	"github.com/Albaihaqi354/Tickitz-BE/core/model"
 	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
 )
 
 type OrderService struct {
-	orderRepository repository.OrderRepo
-	db              *pgxpool.Pool
+	orderRepository     repository.OrderRepo
+	seatHoldRepository  *repository.SeatHoldRepository
+	seatEventRepository *repository.SeatEventRepository
+	pointService        *PointService
+	pricingRepository   repository.PricingRepo
+	voucherService      *VoucherService
+	paymentService      *PaymentService
+	db                  *pgxpool.Pool
 }
 
-func NewOrderService(orderRepository repository.OrderRepo, db *pgxpool.Pool) *OrderService {
+func NewOrderService(orderRepository repository.OrderRepo, seatHoldRepository *repository.SeatHoldRepository, seatEventRepository *repository.SeatEventRepository, pointService *PointService, pricingRepository repository.PricingRepo, voucherService *VoucherService, paymentService *PaymentService, db *pgxpool.Pool) *OrderService {
 	return &OrderService{
-		orderRepository: orderRepository,
-		db:              db,
+		orderRepository:     orderRepository,
+		seatHoldRepository:  seatHoldRepository,
+		seatEventRepository: seatEventRepository,
+		pointService:        pointService,
+		pricingRepository:   pricingRepository,
+		voucherService:      voucherService,
+		paymentService:      paymentService,
+		db:                  db,
 	}
 }
 
+func seatHoldTTL() time.Duration {
+	return pkg.GetEnvDuration("SEAT_HOLD_TTL", 10*time.Minute)
+}
+
+func orderPendingTTL() time.Duration {
+	return pkg.GetEnvDuration("ORDER_PENDING_TTL", 15*time.Minute)
+}
+
+const ticketQRCodeSize = 512
+
+func orderCancelCutoff() time.Duration {
+	return pkg.GetEnvDuration("ORDER_CANCEL_CUTOFF", time.Hour)
+}
+
 func (o OrderService) GetSchedules(ctx context.Context, movieId int, showDate *string, city *string) ([]dto.GetSchedules, error) {
 	schedules, err := o.orderRepository.GetSchedules(ctx, o.db, movieId, showDate, city)
 	if err != nil {
@@ -43,6 +82,8 @@ func (o OrderService) GetSchedules(ctx context.Context, movieId int, showDate *s
 			CinemaLogo:     s.CinemaLogo,
 			CinemaLocation: s.CinemaLocation,
 			CinemaCity:     s.CinemaCity,
+			AuditoriumId:   s.AuditoriumId,
+			AuditoriumName: s.AuditoriumName,
 		})
 	}
 	return response, nil
@@ -55,69 +96,991 @@ func (o OrderService) GetSeatsByScheduleID(ctx context.Context, scheduleId int)
 		return nil, err
 	}
 
+	seatIds := make([]int, 0, len(seats))
+	for _, s := range seats {
+		seatIds = append(seatIds, s.SeatId)
+	}
+	held, err := o.seatHoldRepository.GetHeldSeats(ctx, scheduleId, seatIds)
+	if err != nil {
+		log.Println("Service Error (GetHeldSeats):", err.Error())
+		return nil, err
+	}
+
+	prices := make(map[int]int, len(seats))
+	if len(seats) > 0 {
+		prices, err = o.seatPrices(ctx, o.db, scheduleId, seats)
+		if err != nil {
+			log.Println("Service Error (SeatPrices):", err.Error())
+			return nil, err
+		}
+	}
+
 	var response []dto.SeatResponse
 	for _, s := range seats {
+		if s.Status == "available" && held[s.SeatId] {
+			s.Status = "held"
+		}
 		response = append(response, dto.SeatResponse{
-			SeatId:     s.SeatId,
-			RowLetter:  s.RowLetter,
-			SeatNumber: s.SeatNumber,
-			SeatType:   s.SeatType,
-			Status:     s.Status,
+			SeatId:         s.SeatId,
+			AuditoriumId:   s.AuditoriumId,
+			AuditoriumName: s.AuditoriumName,
+			RowLetter:      s.RowLetter,
+			SeatNumber:     s.SeatNumber,
+			SeatType:       s.SeatType,
+			Status:         s.Status,
+			Price:          prices[s.SeatId],
 		})
 	}
 	return response, nil
 }
 
-func (o OrderService) CreateOrder(ctx context.Context, userId int, req dto.CreateOrderRequest) (int, string, time.Time, error) {
-	price, err := o.orderRepository.GetPriceFromSchedule(ctx, o.db, req.ScheduleId)
+func (o OrderService) CreateOrder(ctx context.Context, userId int, req dto.CreateOrderRequest) (dto.CreateOrderResponse, error) {
+	seats, err := o.validateSeats(ctx, req.ScheduleId, req.Seats)
 	if err != nil {
-		log.Println("Service Error (GetPrice):", err.Error())
-		return 0, "", time.Time{}, err
+		log.Println("Service Error (ValidateSeats):", err.Error())
+		return dto.CreateOrderResponse{}, err
 	}
 
-	totalPrice := price * len(req.Seats)
+	prices, err := o.seatPrices(ctx, o.db, req.ScheduleId, seats)
+	if err != nil {
+		log.Println("Service Error (SeatPrices):", err.Error())
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CreateOrderResponse{}, apperr.ErrScheduleNotFound
+		}
+		return dto.CreateOrderResponse{}, err
+	}
 
-	order := model.Order{
-		UserId:        userId,
-		ScheduleId:    req.ScheduleId,
-		TotalPrice:    totalPrice,
-		PaymentStatus: "pending",
+	method, err := o.paymentService.FindPaymentMethod(ctx, req.PaymentMethod)
+	if err != nil {
+		log.Println("Service Error (FindPaymentMethod):", err.Error())
+		return dto.CreateOrderResponse{}, err
+	}
+
+	items := make([]dto.OrderItem, 0, len(seats))
+	seatPrices := make([]int, 0, len(seats))
+	subtotal := 0
+	for _, seat := range seats {
+		price := prices[seat.SeatId]
+		items = append(items, dto.OrderItem{
+			SeatId:     seat.SeatId,
+			RowLetter:  seat.RowLetter,
+			SeatNumber: seat.SeatNumber,
+			SeatType:   seat.SeatType,
+			Price:      price,
+		})
+		seatPrices = append(seatPrices, price)
+		subtotal += price
 	}
 
 	tx, err := o.db.Begin(ctx)
 	if err != nil {
 		log.Println("Service Error (Begin Tx):", err.Error())
-		return 0, "", time.Time{}, err
+		return dto.CreateOrderResponse{}, err
 	}
 	defer tx.Rollback(ctx)
 
+	var voucher model.Voucher
+	discount := 0
+	if req.PromoCode != "" {
+		voucher, discount, err = o.voucherService.ReserveVoucher(ctx, tx, req.PromoCode, userId, req.ScheduleId, subtotal)
+		if err != nil {
+			log.Println("Service Error (ReserveVoucher):", err.Error())
+			return dto.CreateOrderResponse{}, err
+		}
+	}
+	totalPrice := subtotal - discount
+
+	pointsUsed, pointsValue := 0, 0
+	if req.RedeemPoints > 0 {
+		pointsUsed, pointsValue, err = o.pointService.ReservePoints(ctx, tx, userId, req.RedeemPoints, totalPrice)
+		if err != nil {
+			log.Println("Service Error (ReservePoints):", err.Error())
+			return dto.CreateOrderResponse{}, err
+		}
+		totalPrice -= pointsValue
+	}
+
+	order := model.Order{
+		UserId:        userId,
+		ScheduleId:    req.ScheduleId,
+		TotalPrice:    totalPrice,
+		PaymentStatus: model.OrderStatusPending,
+	}
+
 	id, bookingCode, createdAt, err := o.orderRepository.InsertOrder(ctx, tx, order)
 	if err != nil {
 		log.Println("Service Error (InsertOrder):", err.Error())
-		return 0, "", time.Time{}, err
+		return dto.CreateOrderResponse{}, err
 	}
 
-	for _, seatId := range req.Seats {
-		err := o.orderRepository.InsertOrderDetail(ctx, tx, id, seatId)
-		if err != nil {
-			log.Println("Service Error (InsertOrderDetail):", err.Error())
-			return 0, "", time.Time{}, err
+	if voucher.Id != 0 {
+		if err := o.voucherService.RecordUsage(ctx, tx, voucher, userId, id, discount); err != nil {
+			log.Println("Service Error (RecordUsage):", err.Error())
+			return dto.CreateOrderResponse{}, err
+		}
+	}
+
+	if pointsUsed > 0 {
+		order.Id = id
+		order.BookingCode = bookingCode
+		if err := o.pointService.RedeemPoints(ctx, tx, order, pointsUsed); err != nil {
+			log.Println("Service Error (RedeemPoints):", err.Error())
+			return dto.CreateOrderResponse{}, err
 		}
 	}
 
+	taken, err := o.seatHoldRepository.HoldSeats(ctx, req.ScheduleId, id, req.Seats, seatHoldTTL())
+	if err != nil {
+		log.Println("Service Error (HoldSeats):", err.Error())
+		return dto.CreateOrderResponse{}, err
+	}
+	if len(taken) > 0 {
+		log.Println("Service Error (HoldSeats): seats already held:", taken)
+		return dto.CreateOrderResponse{}, &apperr.SeatTakenError{SeatIds: taken}
+	}
+
+	inserted, err := o.orderRepository.InsertOrderDetails(ctx, tx, id, req.Seats, seatPrices)
+	if err != nil {
+		log.Println("Service Error (InsertOrderDetails):", err.Error())
+		o.releaseSeats(ctx, req.ScheduleId, id, req.Seats)
+		return dto.CreateOrderResponse{}, err
+	}
+	if conflicts := missingSeats(req.Seats, inserted); len(conflicts) > 0 {
+		log.Println("Service Error (InsertOrderDetails): seats already booked:", conflicts)
+		o.releaseSeats(ctx, req.ScheduleId, id, req.Seats)
+		return dto.CreateOrderResponse{}, &apperr.SeatTakenError{SeatIds: conflicts}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		log.Println("Service Error (Commit Tx):", err.Error())
-		return 0, "", time.Time{}, err
+		o.releaseSeats(ctx, req.ScheduleId, id, req.Seats)
+		return dto.CreateOrderResponse{}, err
+	}
+	o.publishSeatEvent(ctx, req.ScheduleId, req.Seats, "held")
+
+	order.Id = id
+	order.BookingCode = bookingCode
+	order.CreatedAt = createdAt
+
+	payment, err := o.paymentService.StartPayment(ctx, order, method)
+	if err != nil {
+		// Without a payment attempt the order can never be paid, so give the
+		// seats back right away instead of waiting for it to expire.
+		_, cancelErr := o.changeOrderStatus(ctx, orderStatusChange{
+			OrderId: id,
+			Status:  model.OrderStatusCancelled,
+			Source:  model.OrderStatusSourcePayment,
+			Note:    "payment could not be started",
+		})
+		if cancelErr != nil {
+			log.Println("Service Error (CancelOrder):", cancelErr.Error())
+		}
+		return dto.CreateOrderResponse{}, err
+	}
+
+	return dto.CreateOrderResponse{
+		Id:            id,
+		BookingCode:   bookingCode,
+		Items:         items,
+		Subtotal:      subtotal,
+		Discount:      discount,
+		PromoCode:     voucher.Code,
+		PointsUsed:    pointsUsed,
+		PointsValue:   pointsValue,
+		TotalPrice:    totalPrice,
+		PaymentStatus: order.PaymentStatus,
+		Payment:       payment,
+		CreatedAt:     createdAt,
+	}, nil
+}
+
+// orderTransitions lists, for every payment status, the statuses an order
+// may move to next. Statuses without an entry are final.
+var orderTransitions = map[string][]string{
+	model.OrderStatusPending: {model.OrderStatusPaid, model.OrderStatusFailed, model.OrderStatusExpired, model.OrderStatusCancelled},
+	model.OrderStatusPaid:    {model.OrderStatusRefunded},
+}
+
+func isKnownStatus(status string) bool {
+	if _, ok := orderTransitions[status]; ok {
+		return true
+	}
+	for _, next := range orderTransitions {
+		if slices.Contains(next, status) {
+			return true
+		}
 	}
+	return false
+}
 
-	return id, bookingCode, createdAt, nil
+func canTransition(from string, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
 }
 
-func (o OrderService) UpdatePaymentStatus(ctx context.Context, orderId int, status string) error {
-	err := o.orderRepository.UpdatePaymentStatus(ctx, o.db, orderId, status)
+// UpdatePaymentStatus sets the payment status of an order by hand. Cancelling
+// and refunding go through CancelOrder, so the owner's cancellation cutoff
+// and the provider refund apply however they are requested. Owners can not
+// set any other status; those changes are driven by payments, background
+// jobs or admins.
+func (o OrderService) UpdatePaymentStatus(ctx context.Context, userId int, role string, orderId int, status string) error {
+	if !isKnownStatus(status) {
+		return apperr.ErrInvalidPaymentStatus
+	}
+	if status == model.OrderStatusCancelled || status == model.OrderStatusRefunded {
+		return o.cancelWithStatus(ctx, userId, role == "admin", orderId, status)
+	}
+
+	source := model.OrderStatusSourceAdmin
+	var authorize func(order model.Order) error
+	if role != "admin" {
+		source = model.OrderStatusSourceUser
+		authorize = func(order model.Order) error {
+			if order.UserId != userId {
+				return apperr.ErrOrderForbidden
+			}
+			return apperr.ErrStatusNotAllowed
+		}
+	}
+
+	_, err := o.changeOrderStatus(ctx, orderStatusChange{
+		OrderId:   orderId,
+		Status:    status,
+		Source:    source,
+		ChangedBy: &userId,
+		Authorize: authorize,
+	})
 	if err != nil {
 		log.Println("Service Error (UpdatePaymentStatus):", err.Error())
 		return err
 	}
 	return nil
 }
+
+// cancelWithStatus cancels an order through CancelOrder when status is the
+// one CancelOrder would move it to.
+func (o OrderService) cancelWithStatus(ctx context.Context, userId int, asAdmin bool, orderId int, status string) error {
+	order, err := o.orderRepository.GetOrderById(ctx, o.db, orderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrOrderNotFound
+		}
+		return err
+	}
+	if !asAdmin && order.UserId != userId {
+		return apperr.ErrOrderForbidden
+	}
+	if cancelStatus(order.PaymentStatus) != status {
+		return &apperr.InvalidTransitionError{From: order.PaymentStatus, To: status}
+	}
+
+	_, err = o.CancelOrder(ctx, userId, orderId, asAdmin)
+	return err
+}
+
+// cancelStatus is the status cancelling an order in the given status leads
+// to: paid orders are refunded, the others cancelled.
+func cancelStatus(paymentStatus string) string {
+	if paymentStatus == model.OrderStatusPaid {
+		return model.OrderStatusRefunded
+	}
+	return model.OrderStatusCancelled
+}
+
+// orderStatusChange describes one payment status change. Authorize, when
+// set, runs against the locked order before anything changes, and Apply runs
+// inside the same transaction right after the order is updated.
+type orderStatusChange struct {
+	OrderId   int
+	Status    string
+	Source    string
+	ChangedBy *int
+	Note      string
+	Authorize func(order model.Order) error
+	Apply     func(ctx context.Context, tx pgx.Tx, order model.Order) error
+}
+
+// changeOrderStatus moves an order to a new payment status following
+// orderTransitions and records the change in order_status_histories.
+func (o OrderService) changeOrderStatus(ctx context.Context, change orderStatusChange) (model.Order, error) {
+	tx, err := o.db.Begin(ctx)
+	if err != nil {
+		return model.Order{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := o.orderRepository.GetOrderByIdForUpdate(ctx, tx, change.OrderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Order{}, apperr.ErrOrderNotFound
+		}
+		return model.Order{}, err
+	}
+
+	if change.Authorize != nil {
+		if err := change.Authorize(order); err != nil {
+			return model.Order{}, err
+		}
+	}
+
+	if !canTransition(order.PaymentStatus, change.Status) {
+		return model.Order{}, &apperr.InvalidTransitionError{From: order.PaymentStatus, To: change.Status}
+	}
+
+	if err := o.orderRepository.UpdatePaymentStatus(ctx, tx, order.Id, order.PaymentStatus, change.Status); err != nil {
+		return model.Order{}, err
+	}
+
+	history := model.OrderStatusHistory{
+		OrderId:    order.Id,
+		FromStatus: order.PaymentStatus,
+		ToStatus:   change.Status,
+		Source:     change.Source,
+		ChangedBy:  change.ChangedBy,
+		Note:       change.Note,
+	}
+	if err := o.orderRepository.InsertOrderStatusHistory(ctx, tx, history); err != nil {
+		return model.Order{}, err
+	}
+
+	if change.Apply != nil {
+		if err := change.Apply(ctx, tx, order); err != nil {
+			return model.Order{}, err
+		}
+	}
+
+	// Attempts still open once the order stops waiting are closed, whatever
+	// settled it: a webhook settles its own attempt in Apply, and an order
+	// marked paid by an admin was paid outside the gateway. A capture that
+	// arrives for a closed attempt later is refunded.
+	if order.PaymentStatus == model.OrderStatusPending {
+		if err := o.paymentService.CancelPendingPayments(ctx, tx, order.Id); err != nil {
+			return model.Order{}, err
+		}
+	}
+
+	if change.Status == model.OrderStatusPaid {
+		err = o.pointService.AwardPoints(ctx, tx, order)
+	} else {
+		// Every other transition ends the order, so points redeemed on it
+		// are given back and points earned with it taken away.
+		err = o.pointService.ReversePoints(ctx, tx, order)
+	}
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.Order{}, err
+	}
+
+	o.syncOrderSeats(ctx, order, change.Status)
+
+	order.PaymentStatus = change.Status
+	return order, nil
+}
+
+// errPaymentApplied stops a webhook whose status the order already has, so
+// duplicate deliveries are acknowledged without changing anything.
+var errPaymentApplied = errors.New("payment already applied")
+
+// errOrderClosed stops a webhook for an order that expired or was cancelled
+// before its payment attempt was settled.
+var errOrderClosed = errors.New("order no longer awaits payment")
+
+// HandlePaymentWebhook verifies a provider callback and settles the order
+// and its payment attempt. Replaying the same callback is a no-op. A capture
+// for an order that no longer awaits payment is refunded instead.
+func (o OrderService) HandlePaymentWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := o.paymentService.ParseWebhook(payload, signature)
+	if err != nil {
+		log.Println("Service Error (ParseWebhook):", err.Error())
+		return err
+	}
+
+	payment, err := o.paymentService.GetPaymentByReference(ctx, event.Reference)
+	if err != nil {
+		log.Println("Service Error (GetPaymentByReference):", err.Error())
+		return err
+	}
+	if payment.Status == event.Status {
+		return nil
+	}
+	if payment.Amount != event.Amount {
+		return apperr.ErrPaymentAmountMismatch
+	}
+	if payment.Status == pkg.PaymentStatusCancelled {
+		return o.settleClosedPayment(ctx, payment, event, payload)
+	}
+	if event.Status == pkg.PaymentStatusPaid &&
+		(payment.Status == pkg.PaymentStatusRefundPending || payment.Status == pkg.PaymentStatusRefunded) {
+		// A late replay of the capture of a payment that is being or was
+		// given back.
+		return nil
+	}
+	if payment.Status != pkg.PaymentStatusPending {
+		return &apperr.InvalidTransitionError{From: payment.Status, To: event.Status}
+	}
+
+	_, err = o.changeOrderStatus(ctx, orderStatusChange{
+		OrderId: payment.OrderId,
+		Status:  event.Status,
+		Source:  model.OrderStatusSourcePayment,
+		Note:    payment.Provider + ":" + payment.Reference,
+		Authorize: func(order model.Order) error {
+			if order.PaymentStatus == event.Status {
+				return errPaymentApplied
+			}
+			if order.PaymentStatus != model.OrderStatusPending {
+				return errOrderClosed
+			}
+			return nil
+		},
+		Apply: func(ctx context.Context, tx pgx.Tx, order model.Order) error {
+			return o.paymentService.CompletePayment(ctx, tx, payment, event.Status, payload)
+		},
+	})
+	if errors.Is(err, errPaymentApplied) {
+		return nil
+	}
+	if errors.Is(err, errOrderClosed) {
+		return o.settleClosedPayment(ctx, payment, event, payload)
+	}
+	if err != nil {
+		log.Println("Service Error (HandlePaymentWebhook):", err.Error())
+		return err
+	}
+	return nil
+}
+
+// settleClosedPayment records the outcome of an attempt whose order expired
+// or was cancelled in the meantime. Money captured for it is refunded; if the
+// refund fails the webhook fails too, so the provider retries it.
+func (o OrderService) settleClosedPayment(ctx context.Context, payment model.OrderPayment, event pkg.PaymentEvent, payload []byte) error {
+	var err error
+	if event.Status == pkg.PaymentStatusPaid {
+		err = o.paymentService.RefundLateCapture(ctx, payment, payload)
+	} else {
+		err = o.paymentService.CompletePayment(ctx, o.db, payment, event.Status, payload)
+	}
+	if err != nil {
+		log.Println("Service Error (settleClosedPayment):", err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetOrderDetail returns the full e-ticket of an order. Only the owner and
+// admins can read it.
+func (o OrderService) GetOrderDetail(ctx context.Context, userId int, role string, orderId int) (dto.OrderDetailResponse, error) {
+	ticket, err := o.orderRepository.GetOrderTicket(ctx, o.db, orderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.OrderDetailResponse{}, apperr.ErrOrderNotFound
+		}
+		log.Println("Service Error (GetOrderDetail):", err.Error())
+		return dto.OrderDetailResponse{}, err
+	}
+	if role != "admin" && ticket.UserId != userId {
+		return dto.OrderDetailResponse{}, apperr.ErrOrderForbidden
+	}
+
+	seats, err := o.orderRepository.GetSeatsByOrderId(ctx, o.db, orderId)
+	if err != nil {
+		log.Println("Service Error (GetOrderDetail):", err.Error())
+		return dto.OrderDetailResponse{}, err
+	}
+
+	response := dto.OrderDetailResponse{
+		Id:            ticket.Id,
+		BookingCode:   ticket.BookingCode,
+		PaymentStatus: ticket.PaymentStatus,
+		ScheduleId:    ticket.ScheduleId,
+		ShowDate:      ticket.ShowDate,
+		ShowTime:      ticket.ShowTime,
+		Movie: dto.TicketMovie{
+			Id:        ticket.MovieId,
+			Title:     ticket.Title,
+			PosterUrl: ticket.PosterUrl,
+			Duration:  ticket.Duration,
+		},
+		Cinema: dto.TicketCinema{
+			Id:         ticket.CinemaId,
+			Name:       ticket.CinemaName,
+			Logo:       ticket.CinemaLogo,
+			Location:   ticket.CinemaLocation,
+			City:       ticket.CityName,
+			Auditorium: ticket.AuditoriumName,
+		},
+		Seats:     []dto.TicketSeat{},
+		CreatedAt: ticket.CreatedAt,
+	}
+
+	subtotal := 0
+	for _, seat := range seats {
+		response.Seats = append(response.Seats, dto.TicketSeat{
+			SeatId:     seat.SeatId,
+			RowLetter:  seat.RowLetter,
+			SeatNumber: seat.SeatNumber,
+			SeatType:   seat.SeatType,
+			Price:      seat.Price,
+		})
+		subtotal += seat.Price
+	}
+	response.Price = dto.PriceBreakdown{
+		Subtotal: subtotal,
+		Discount: max(subtotal-ticket.TotalPrice, 0),
+		Total:    ticket.TotalPrice,
+	}
+
+	if ticket.PaymentState != nil {
+		response.Payment = &dto.TicketPayment{
+			Method:    deref(ticket.PaymentMethod),
+			Provider:  deref(ticket.PaymentProvider),
+			Reference: deref(ticket.PaymentReference),
+			Status:    deref(ticket.PaymentState),
+		}
+		if ticket.PaymentAmount != nil {
+			response.Payment.Amount = *ticket.PaymentAmount
+		}
+	}
+
+	return response, nil
+}
+
+// GetTicketQRCode returns a PNG QR code of the signed ticket payload, so the
+// gate can verify the ticket without calling the API. Only paid orders get a
+// ticket.
+func (o OrderService) GetTicketQRCode(ctx context.Context, userId int, role string, orderId int) ([]byte, error) {
+	order, err := o.orderRepository.GetOrderById(ctx, o.db, orderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	if role != "admin" && order.UserId != userId {
+		return nil, apperr.ErrOrderForbidden
+	}
+	if order.PaymentStatus != model.OrderStatusPaid {
+		return nil, apperr.ErrOrderNotPaid
+	}
+
+	payload, err := pkg.SignTicket(order.Id, order.BookingCode)
+	if err != nil {
+		log.Println("Service Error (GetTicketQRCode):", err.Error())
+		return nil, err
+	}
+	png, err := pkg.GenerateQRCode(payload, ticketQRCodeSize)
+	if err != nil {
+		log.Println("Service Error (GetTicketQRCode):", err.Error())
+		return nil, err
+	}
+	return png, nil
+}
+
+// GetTicketPDF renders the printable e-ticket of a paid order owned by the
+// caller.
+func (o OrderService) GetTicketPDF(ctx context.Context, userId int, orderId int) ([]byte, error) {
+	detail, err := o.GetOrderDetail(ctx, userId, "user", orderId)
+	if err != nil {
+		return nil, err
+	}
+	if detail.PaymentStatus != model.OrderStatusPaid {
+		return nil, apperr.ErrOrderNotPaid
+	}
+
+	payload, err := pkg.SignTicket(detail.Id, detail.BookingCode)
+	if err != nil {
+		log.Println("Service Error (GetTicketPDF):", err.Error())
+		return nil, err
+	}
+	qr, err := pkg.GenerateQRCode(payload, ticketQRCodeSize)
+	if err != nil {
+		log.Println("Service Error (GetTicketPDF):", err.Error())
+		return nil, err
+	}
+
+	seats := make([]string, 0, len(detail.Seats))
+	for _, seat := range detail.Seats {
+		seats = append(seats, fmt.Sprintf("%s%d", seat.RowLetter, seat.SeatNumber))
+	}
+
+	pdf, err := pkg.RenderTicketPDF(pkg.TicketDocument{
+		Title:          detail.Movie.Title,
+		Poster:         loadPoster(ctx, detail.Movie.PosterUrl),
+		CinemaName:     detail.Cinema.Name,
+		Auditorium:     detail.Cinema.Auditorium,
+		CinemaLocation: detail.Cinema.Location,
+		City:           detail.Cinema.City,
+		ShowDate:       detail.ShowDate,
+		ShowTime:       detail.ShowTime,
+		Seats:          seats,
+		BookingCode:    detail.BookingCode,
+		TotalPrice:     detail.Price.Total,
+		QRCode:         qr,
+	})
+	if err != nil {
+		log.Println("Service Error (GetTicketPDF):", err.Error())
+		return nil, err
+	}
+	return pdf, nil
+}
+
+const maxPosterSize = 5 << 20
+
+// loadPoster reads an uploaded poster from ./public or downloads a remote
+// one. The ticket is still rendered without a poster when this fails.
+func loadPoster(ctx context.Context, posterUrl string) []byte {
+	if posterUrl == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(posterUrl, "http://") || strings.HasPrefix(posterUrl, "https://") {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, posterUrl, nil)
+		if err != nil {
+			return nil
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Println("Load Poster Error:", err.Error())
+			return nil
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxPosterSize))
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+
+	cleaned := path.Clean(posterUrl)
+	if !strings.HasPrefix(cleaned, "/movie/") {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join("public", filepath.FromSlash(cleaned)))
+	if err != nil {
+		log.Println("Load Poster Error:", err.Error())
+		return nil
+	}
+	return data
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// CancelOrder cancels a pending order or refunds a paid one. Owners can only
+// cancel until ORDER_CANCEL_CUTOFF before the show starts; admins can always
+// cancel. Loyalty points tied to the order are reversed by changeOrderStatus
+// and the seats freed.
+func (o OrderService) CancelOrder(ctx context.Context, userId int, orderId int, asAdmin bool) (dto.CancelOrderResponse, error) {
+	order, err := o.orderRepository.GetOrderById(ctx, o.db, orderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.CancelOrderResponse{}, apperr.ErrOrderNotFound
+		}
+		return dto.CancelOrderResponse{}, err
+	}
+	if !asAdmin && order.UserId != userId {
+		return dto.CancelOrderResponse{}, apperr.ErrOrderForbidden
+	}
+
+	status := cancelStatus(order.PaymentStatus)
+
+	if !asAdmin {
+		untilShow, err := o.orderRepository.GetTimeUntilShow(ctx, o.db, order.ScheduleId)
+		if err != nil {
+			return dto.CancelOrderResponse{}, err
+		}
+		if untilShow < orderCancelCutoff() {
+			return dto.CancelOrderResponse{}, apperr.ErrCancellationClosed
+		}
+	}
+
+	source := model.OrderStatusSourceUser
+	note := "cancelled by owner"
+	if asAdmin {
+		source = model.OrderStatusSourceAdmin
+		note = "cancelled by admin"
+	}
+
+	updated, err := o.changeOrderStatus(ctx, orderStatusChange{
+		OrderId:   orderId,
+		Status:    status,
+		Source:    source,
+		ChangedBy: &userId,
+		Note:      note,
+		Authorize: func(locked model.Order) error {
+			if locked.PaymentStatus != order.PaymentStatus {
+				return &apperr.InvalidTransitionError{From: locked.PaymentStatus, To: status}
+			}
+			return nil
+		},
+		Apply: func(ctx context.Context, tx pgx.Tx, order model.Order) error {
+			if status == model.OrderStatusRefunded {
+				return o.paymentService.RequestRefund(ctx, tx, order.Id)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		log.Println("Service Error (CancelOrder):", err.Error())
+		return dto.CancelOrderResponse{}, err
+	}
+
+	if status == model.OrderStatusRefunded {
+		// The order is refunded either way; a failed provider refund stays
+		// pending and is retried by the order expiry worker.
+		if err := o.paymentService.ProcessRefunds(ctx, updated.Id); err != nil {
+			log.Println("Service Error (CancelOrder):", err.Error())
+		}
+	}
+
+	return dto.CancelOrderResponse{
+		Id:            updated.Id,
+		BookingCode:   updated.BookingCode,
+		PaymentStatus: updated.PaymentStatus,
+	}, nil
+}
+
+// errOrderSettled skips orders that left the pending state while a
+// background job was looking at them.
+var errOrderSettled = errors.New("order already settled")
+
+// ExpirePendingOrders expires the pending orders older than ORDER_PENDING_TTL
+// and returns how many were expired. Every order is re-checked under a row
+// lock, so several instances can run it at the same time.
+func (o OrderService) ExpirePendingOrders(ctx context.Context) (int, error) {
+	orderIds, err := o.orderRepository.GetStalePendingOrderIds(ctx, o.db, orderPendingTTL(), 100)
+	if err != nil {
+		log.Println("Service Error (GetStalePendingOrderIds):", err.Error())
+		return 0, err
+	}
+
+	expired := 0
+	for _, orderId := range orderIds {
+		_, err := o.changeOrderStatus(ctx, orderStatusChange{
+			OrderId: orderId,
+			Status:  model.OrderStatusExpired,
+			Source:  model.OrderStatusSourceSystem,
+			Note:    "payment window elapsed",
+			Authorize: func(order model.Order) error {
+				if order.PaymentStatus != model.OrderStatusPending {
+					return errOrderSettled
+				}
+				return nil
+			},
+		})
+		if errors.Is(err, errOrderSettled) {
+			continue
+		}
+		if err != nil {
+			log.Println("Service Error (ExpireOrder):", orderId, err.Error())
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// RetryRefunds retries provider refunds that failed after their order was
+// refunded and returns how many went through.
+func (o OrderService) RetryRefunds(ctx context.Context) (int, error) {
+	refunded, err := o.paymentService.RetryRefunds(ctx, 100)
+	if err != nil {
+		log.Println("Service Error (RetryRefunds):", err.Error())
+		return 0, err
+	}
+	return refunded, nil
+}
+
+func (o OrderService) SimulatePayment(ctx context.Context, reference string, status string) error {
+	payload, signature, err := o.paymentService.SimulatePayment(ctx, reference, status)
+	if err != nil {
+		log.Println("Service Error (SimulatePayment):", err.Error())
+		return err
+	}
+	return o.HandlePaymentWebhook(ctx, payload, signature)
+}
+
+// syncOrderSeats runs after an order moved to status. Holds of an order that
+// left pending are dropped, since paid seats are reported as sold from the
+// database from then on, and seat map streams learn the new seat status.
+func (o OrderService) syncOrderSeats(ctx context.Context, order model.Order, status string) {
+	seatIds, err := o.orderRepository.GetSeatIdsByOrderId(ctx, o.db, order.Id)
+	if err != nil {
+		log.Println("Service Error (GetSeatIdsByOrderId):", err.Error())
+		return
+	}
+	if order.PaymentStatus == model.OrderStatusPending {
+		o.releaseSeats(ctx, order.ScheduleId, order.Id, seatIds)
+	}
+
+	seatStatus := "available"
+	if status == model.OrderStatusPaid {
+		seatStatus = "sold"
+	}
+	o.publishSeatEvent(ctx, order.ScheduleId, seatIds, seatStatus)
+}
+
+// publishSeatEvent is best effort: clients that miss an event still get the
+// right state from the next snapshot.
+func (o OrderService) publishSeatEvent(ctx context.Context, scheduleId int, seatIds []int, status string) {
+	if len(seatIds) == 0 {
+		return
+	}
+	payload, err := json.Marshal(dto.SeatEvent{
+		ScheduleId: scheduleId,
+		SeatIds:    seatIds,
+		Status:     status,
+	})
+	if err != nil {
+		log.Println("Service Error (PublishSeatEvent):", err.Error())
+		return
+	}
+	if err := o.seatEventRepository.Publish(ctx, scheduleId, payload); err != nil {
+		log.Println("Service Error (PublishSeatEvent):", err.Error())
+	}
+}
+
+// SubscribeSeatEvents streams the seat status changes of a schedule until ctx
+// is done. The subscription is active when it returns, so a snapshot taken
+// afterwards can not miss a change. The channel is also closed when the
+// subscriber falls behind, so the client can resync from a new snapshot.
+func (o OrderService) SubscribeSeatEvents(ctx context.Context, scheduleId int) (<-chan dto.SeatEvent, error) {
+	messages, err := o.seatEventRepository.Subscribe(ctx, scheduleId)
+	if err != nil {
+		log.Println("Service Error (SubscribeSeatEvents):", err.Error())
+		return nil, err
+	}
+
+	events := make(chan dto.SeatEvent)
+	go func() {
+		defer close(events)
+
+		for payload := range messages {
+			var event dto.SeatEvent
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				log.Println("Service Error (SubscribeSeatEvents):", err.Error())
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// validateSeats makes sure the requested seats are unique, exist and belong
+// to the cinema the schedule plays in, and returns them in the requested
+// order.
+func (o OrderService) validateSeats(ctx context.Context, scheduleId int, seatIds []int) ([]model.Seat, error) {
+	if len(seatIds) == 0 {
+		return nil, apperr.ErrNoSeatsSelected
+	}
+
+	seen := make(map[int]bool, len(seatIds))
+	var duplicates []int
+	for _, seatId := range seatIds {
+		if seen[seatId] {
+			duplicates = append(duplicates, seatId)
+		}
+		seen[seatId] = true
+	}
+	if len(duplicates) > 0 {
+		return nil, &apperr.InvalidSeatsError{Reason: "duplicate seats", SeatIds: duplicates}
+	}
+
+	auditoriumId, err := o.orderRepository.GetAuditoriumIdFromSchedule(ctx, o.db, scheduleId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	seats, err := o.orderRepository.GetSeatsByIds(ctx, o.db, seatIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int]model.Seat, len(seats))
+	found := make([]int, 0, len(seats))
+	var otherAuditorium []int
+	for _, s := range seats {
+		byId[s.SeatId] = s
+		found = append(found, s.SeatId)
+		if s.AuditoriumId != auditoriumId {
+			otherAuditorium = append(otherAuditorium, s.SeatId)
+		}
+	}
+	if notFound := missingSeats(seatIds, found); len(notFound) > 0 {
+		return nil, &apperr.InvalidSeatsError{Reason: "seats not found", SeatIds: notFound}
+	}
+	if len(otherAuditorium) > 0 {
+		return nil, &apperr.InvalidSeatsError{Reason: "seats do not belong to the schedule's auditorium", SeatIds: otherAuditorium}
+	}
+
+	ordered := make([]model.Seat, 0, len(seatIds))
+	for _, seatId := range seatIds {
+		ordered = append(ordered, byId[seatId])
+	}
+	return ordered, nil
+}
+
+// seatPrices prices every seat from the schedule price and the seat type
+// rule of the schedule or its cinema. Seat types without a rule pay the
+// schedule price.
+func (o OrderService) seatPrices(ctx context.Context, db repository.DBTX, scheduleId int, seats []model.Seat) (map[int]int, error) {
+	basePrice, err := o.orderRepository.GetPriceFromSchedule(ctx, db, scheduleId)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := o.pricingRepository.GetSeatTypePricesForSchedule(ctx, db, scheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[int]int, len(seats))
+	for _, seat := range seats {
+		price := basePrice
+		if rule, ok := rules[seat.SeatType]; ok {
+			price = rule.Apply(basePrice)
+		}
+		prices[seat.SeatId] = price
+	}
+	return prices, nil
+}
+
+func missingSeats(requested []int, inserted []int) []int {
+	booked := make(map[int]bool, len(inserted))
+	for _, seatId := range inserted {
+		booked[seatId] = true
+	}
+
+	var missing []int
+	for _, seatId := range requested {
+		if !booked[seatId] {
+			missing = append(missing, seatId)
+		}
+	}
+	return missing
+}
+
+func (o OrderService) releaseSeats(ctx context.Context, scheduleId int, orderId int, seatIds []int) {
+	if err := o.seatHoldRepository.ReleaseSeats(ctx, scheduleId, orderId, seatIds); err != nil {
+		log.Println("Service Error (ReleaseSeats):", err.Error())
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{model.OrderStatusPending, model.OrderStatusPaid, true},
+		{model.OrderStatusPending, model.OrderStatusFailed, true},
+		{model.OrderStatusPending, model.OrderStatusExpired, true},
+		{model.OrderStatusPending, model.OrderStatusCancelled, true},
+		{model.OrderStatusPending, model.OrderStatusRefunded, false},
+		{model.OrderStatusPending, model.OrderStatusPending, false},
+		{model.OrderStatusPaid, model.OrderStatusRefunded, true},
+		{model.OrderStatusPaid, model.OrderStatusCancelled, false},
+		{model.OrderStatusPaid, model.OrderStatusPending, false},
+		{model.OrderStatusExpired, model.OrderStatusPaid, false},
+		{model.OrderStatusCancelled, model.OrderStatusPaid, false},
+		{model.OrderStatusFailed, model.OrderStatusPending, false},
+		{model.OrderStatusRefunded, model.OrderStatusPaid, false},
+		{"unknown", model.OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestIsKnownStatus(t *testing.T) {
+	for _, status := range []string{
+		model.OrderStatusPending,
+		model.OrderStatusPaid,
+		model.OrderStatusExpired,
+		model.OrderStatusCancelled,
+		model.OrderStatusRefunded,
+		model.OrderStatusFailed,
+	} {
+		if !isKnownStatus(status) {
+			t.Errorf("isKnownStatus(%q) = false, want true", status)
+		}
+	}
+	for _, status := range []string{"", "PAID", "unknown"} {
+		if isKnownStatus(status) {
+			t.Errorf("isKnownStatus(%q) = true, want false", status)
+		}
+	}
+}
+
+func TestCancelStatus(t *testing.T) {
+	tests := []struct {
+		paymentStatus string
+		want          string
+	}{
+		{model.OrderStatusPending, model.OrderStatusCancelled},
+		{model.OrderStatusPaid, model.OrderStatusRefunded},
+		{model.OrderStatusExpired, model.OrderStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		if got := cancelStatus(tt.paymentStatus); got != tt.want {
+			t.Errorf("cancelStatus(%q) = %q, want %q", tt.paymentStatus, got, tt.want)
+		}
+		if tt.paymentStatus != model.OrderStatusExpired && !canTransition(tt.paymentStatus, cancelStatus(tt.paymentStatus)) {
+			t.Errorf("cancelling a %s order is not an allowed transition", tt.paymentStatus)
+		}
+	}
+}
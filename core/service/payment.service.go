@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+type PaymentService struct {
+	paymentRepository repository.PaymentRepo
+	provider          pkg.PaymentProvider
+	db                *pgxpool.Pool
+}
+
+func NewPaymentService(paymentRepository repository.PaymentRepo, provider pkg.PaymentProvider, db *pgxpool.Pool) *PaymentService {
+	return &PaymentService{
+		paymentRepository: paymentRepository,
+		provider:          provider,
+		db:                db,
+	}
+}
+
+func (p PaymentService) FindPaymentMethod(ctx context.Context, name string) (model.PaymentMethod, error) {
+	method, err := p.paymentRepository.GetPaymentMethodByName(ctx, p.db, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.PaymentMethod{}, apperr.ErrPaymentMethodNotFound
+		}
+		return model.PaymentMethod{}, err
+	}
+	return method, nil
+}
+
+// StartPayment opens a payment attempt for the order at the provider and
+// records it in order_payments.
+func (p PaymentService) StartPayment(ctx context.Context, order model.Order, method model.PaymentMethod) (dto.PaymentSession, error) {
+	session, err := p.provider.CreatePayment(ctx, pkg.PaymentRequest{
+		OrderId:     order.Id,
+		BookingCode: order.BookingCode,
+		Amount:      order.TotalPrice,
+		Method:      method.Name,
+	})
+	if err != nil {
+		log.Println("Service Error (CreatePayment):", err.Error())
+		return dto.PaymentSession{}, err
+	}
+
+	payment := model.OrderPayment{
+		OrderId:         order.Id,
+		PaymentMethodId: method.Id,
+		Provider:        p.provider.Name(),
+		Reference:       session.Reference,
+		Amount:          order.TotalPrice,
+		PaymentUrl:      session.PaymentUrl,
+		Status:          pkg.PaymentStatusPending,
+	}
+	if _, err := p.paymentRepository.InsertOrderPayment(ctx, p.db, payment); err != nil {
+		log.Println("Service Error (InsertOrderPayment):", err.Error())
+		return dto.PaymentSession{}, err
+	}
+
+	return dto.PaymentSession{
+		Provider:      payment.Provider,
+		PaymentMethod: method.Name,
+		Reference:     session.Reference,
+		PaymentUrl:    session.PaymentUrl,
+		Amount:        payment.Amount,
+		Status:        payment.Status,
+	}, nil
+}
+
+func (p PaymentService) ParseWebhook(payload []byte, signature string) (pkg.PaymentEvent, error) {
+	event, err := p.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, pkg.ErrInvalidSignature) {
+			return pkg.PaymentEvent{}, err
+		}
+		return pkg.PaymentEvent{}, apperr.ErrInvalidPaymentPayload
+	}
+	if event.Status != pkg.PaymentStatusPaid && event.Status != pkg.PaymentStatusFailed {
+		return pkg.PaymentEvent{}, apperr.ErrInvalidPaymentStatus
+	}
+	return event, nil
+}
+
+func (p PaymentService) GetPaymentByReference(ctx context.Context, reference string) (model.OrderPayment, error) {
+	payment, err := p.paymentRepository.GetOrderPaymentByReference(ctx, p.db, reference)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.OrderPayment{}, apperr.ErrPaymentNotFound
+		}
+		return model.OrderPayment{}, err
+	}
+	return payment, nil
+}
+
+func (p PaymentService) CompletePayment(ctx context.Context, db repository.DBTX, payment model.OrderPayment, status string, payload []byte) error {
+	return p.paymentRepository.UpdateOrderPaymentStatus(ctx, db, payment.Id, payment.Status, status, string(payload))
+}
+
+// RequestRefund marks every paid attempt of the order refund_pending. It runs
+// inside the transaction that refunds the order; the provider is only called
+// by ProcessRefunds once that transaction committed, so a failed commit never
+// leaves money refunded for an order that is still paid.
+func (p PaymentService) RequestRefund(ctx context.Context, db repository.DBTX, orderId int) error {
+	payments, err := p.paymentRepository.GetOrderPaymentsByStatus(ctx, db, orderId, pkg.PaymentStatusPaid)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		err := p.paymentRepository.UpdateOrderPaymentStatus(ctx, db, payment.Id, payment.Status, pkg.PaymentStatusRefundPending, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessRefunds refunds the refund_pending attempts of the order at the
+// provider and marks them refunded. Attempts whose refund fails stay
+// refund_pending for RetryRefunds.
+func (p PaymentService) ProcessRefunds(ctx context.Context, orderId int) error {
+	payments, err := p.paymentRepository.GetOrderPaymentsByStatus(ctx, p.db, orderId, pkg.PaymentStatusRefundPending)
+	if err != nil {
+		return err
+	}
+	return p.refund(ctx, payments)
+}
+
+// RetryRefunds refunds up to limit refund_pending attempts of any order and
+// returns how many went through. Providers refund a reference at most once,
+// so retrying an attempt whose status update failed is safe.
+func (p PaymentService) RetryRefunds(ctx context.Context, limit int) (int, error) {
+	payments, err := p.paymentRepository.GetPaymentsByStatus(ctx, p.db, pkg.PaymentStatusRefundPending, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := 0
+	for _, payment := range payments {
+		if err := p.refund(ctx, []model.OrderPayment{payment}); err != nil {
+			continue
+		}
+		refunded++
+	}
+	return refunded, nil
+}
+
+func (p PaymentService) refund(ctx context.Context, payments []model.OrderPayment) error {
+	for _, payment := range payments {
+		if err := p.provider.Refund(ctx, payment.Reference, payment.Amount); err != nil {
+			log.Println("Service Error (Refund):", err.Error())
+			return err
+		}
+		err := p.paymentRepository.UpdateOrderPaymentStatus(ctx, p.db, payment.Id, payment.Status, pkg.PaymentStatusRefunded, "")
+		if err != nil {
+			log.Println("Service Error (Refund):", err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// CancelPendingPayments closes the attempts of an order that stopped waiting
+// for payment, so a capture reported for them later is recognised as late.
+func (p PaymentService) CancelPendingPayments(ctx context.Context, db repository.DBTX, orderId int) error {
+	payments, err := p.paymentRepository.GetOrderPaymentsByStatus(ctx, db, orderId, pkg.PaymentStatusPending)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		err := p.paymentRepository.UpdateOrderPaymentStatus(ctx, db, payment.Id, payment.Status, pkg.PaymentStatusCancelled, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefundLateCapture gives back a payment captured after its order expired or
+// was cancelled. The order itself is not touched: its seats may have been
+// sold again already.
+func (p PaymentService) RefundLateCapture(ctx context.Context, payment model.OrderPayment, payload []byte) error {
+	log.Printf("Late capture of payment %s for order %d, refunding %d", payment.Reference, payment.OrderId, payment.Amount)
+	if err := p.provider.Refund(ctx, payment.Reference, payment.Amount); err != nil {
+		log.Println("Service Error (Refund):", err.Error())
+		return err
+	}
+	return p.paymentRepository.UpdateOrderPaymentStatus(ctx, p.db, payment.Id, payment.Status, pkg.PaymentStatusRefunded, string(payload))
+}
+
+// SimulatePayment signs and delivers a webhook event for a payment made
+// through the simulated provider, exactly as the gateway would.
+func (p PaymentService) SimulatePayment(ctx context.Context, reference string, status string) ([]byte, string, error) {
+	simulator, ok := p.provider.(*pkg.SimulatedProvider)
+	if !ok {
+		return nil, "", apperr.ErrPaymentSimulationDisabled
+	}
+
+	payment, err := p.GetPaymentByReference(ctx, reference)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return simulator.Simulate(pkg.PaymentEvent{
+		Reference: payment.Reference,
+		Status:    status,
+		Amount:    payment.Amount,
+	})
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+type fakePaymentRepo struct {
+	repository.PaymentRepo
+	payments map[int]*model.OrderPayment
+}
+
+func (f *fakePaymentRepo) GetOrderPaymentsByStatus(ctx context.Context, db repository.DBTX, orderId int, status string) ([]model.OrderPayment, error) {
+	var payments []model.OrderPayment
+	for _, payment := range f.payments {
+		if payment.OrderId == orderId && payment.Status == status {
+			payments = append(payments, *payment)
+		}
+	}
+	return payments, nil
+}
+
+func (f *fakePaymentRepo) UpdateOrderPaymentStatus(ctx context.Context, db repository.DBTX, paymentId int, fromStatus string, toStatus string, payload string) error {
+	f.payments[paymentId].Status = toStatus
+	return nil
+}
+
+type fakeRefundProvider struct {
+	pkg.PaymentProvider
+	err      error
+	refunded []string
+}
+
+func (f *fakeRefundProvider) Refund(ctx context.Context, reference string, amount int) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.refunded = append(f.refunded, reference)
+	return nil
+}
+
+func TestRefundAfterCommit(t *testing.T) {
+	repo := &fakePaymentRepo{payments: map[int]*model.OrderPayment{
+		1: {Id: 1, OrderId: 5, Reference: "SIM-5-a", Amount: 50000, Status: pkg.PaymentStatusPaid},
+		2: {Id: 2, OrderId: 5, Reference: "SIM-5-b", Amount: 50000, Status: pkg.PaymentStatusCancelled},
+	}}
+	provider := &fakeRefundProvider{err: errors.New("gateway down")}
+	p := PaymentService{paymentRepository: repo, provider: provider}
+	ctx := context.Background()
+
+	if err := p.RequestRefund(ctx, nil, 5); err != nil {
+		t.Fatalf("RequestRefund() error = %v", err)
+	}
+	if repo.payments[1].Status != pkg.PaymentStatusRefundPending || len(provider.refunded) != 0 {
+		t.Fatalf("after RequestRefund: status %q, refunded %v, want refund_pending and no provider call", repo.payments[1].Status, provider.refunded)
+	}
+	if repo.payments[2].Status != pkg.PaymentStatusCancelled {
+		t.Errorf("cancelled attempt status = %q, want it untouched", repo.payments[2].Status)
+	}
+
+	if err := p.ProcessRefunds(ctx, 5); err == nil {
+		t.Fatal("ProcessRefunds() with a failing provider succeeded")
+	}
+	if repo.payments[1].Status != pkg.PaymentStatusRefundPending {
+		t.Fatalf("status after failed refund = %q, want it kept for a retry", repo.payments[1].Status)
+	}
+
+	provider.err = nil
+	if err := p.ProcessRefunds(ctx, 5); err != nil {
+		t.Fatalf("ProcessRefunds() error = %v", err)
+	}
+	if repo.payments[1].Status != pkg.PaymentStatusRefunded || len(provider.refunded) != 1 || provider.refunded[0] != "SIM-5-a" {
+		t.Errorf("after ProcessRefunds: status %q, refunded %v, want refunded SIM-5-a", repo.payments[1].Status, provider.refunded)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+type PointService struct {
+	pointRepository repository.PointRepo
+	db              *pgxpool.Pool
+}
+
+func NewPointService(pointRepository repository.PointRepo, db *pgxpool.Pool) *PointService {
+	return &PointService{
+		pointRepository: pointRepository,
+		db:              db,
+	}
+}
+
+// pointEarnAmount is how much a user has to pay to earn one point.
+func pointEarnAmount() int {
+	return pkg.GetEnvInt("LOYALTY_EARN_AMOUNT", 1000)
+}
+
+// pointValue is the discount one point is worth at checkout.
+func pointValue() int {
+	return pkg.GetEnvInt("LOYALTY_POINT_VALUE", 100)
+}
+
+func (p PointService) GetPoints(ctx context.Context, userId int) (dto.PointsResponse, error) {
+	balance, err := p.pointRepository.GetPointBalance(ctx, p.db, userId)
+	if err != nil {
+		log.Println("Service Error (GetPoints):", err.Error())
+		return dto.PointsResponse{}, err
+	}
+	transactions, err := p.pointRepository.GetPointTransactions(ctx, p.db, userId)
+	if err != nil {
+		log.Println("Service Error (GetPoints):", err.Error())
+		return dto.PointsResponse{}, err
+	}
+
+	response := dto.PointsResponse{
+		Balance:      balance,
+		PointValue:   pointValue(),
+		Transactions: make([]dto.PointTransactionResponse, 0, len(transactions)),
+	}
+	for _, t := range transactions {
+		response.Transactions = append(response.Transactions, dto.PointTransactionResponse{
+			Id:           t.Id,
+			OrderId:      t.OrderId,
+			PointsChange: t.PointsChange,
+			Description:  t.Description,
+			CreatedAt:    t.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// ReservePoints locks the user's balance and returns how many of the
+// requested points are used for an order of amount, and the discount they
+// give. Points beyond what the order needs are not used.
+func (p PointService) ReservePoints(ctx context.Context, tx pgx.Tx, userId int, points int, amount int) (int, int, error) {
+	balance, err := p.pointRepository.GetPointBalanceForUpdate(ctx, tx, userId)
+	if err != nil {
+		return 0, 0, err
+	}
+	if points > balance {
+		return 0, 0, apperr.ErrInsufficientPoints
+	}
+
+	value := pointValue()
+	used := min(points, amount/value)
+	return used, used * value, nil
+}
+
+func (p PointService) RedeemPoints(ctx context.Context, tx pgx.Tx, order model.Order, points int) error {
+	return p.pointRepository.InsertPointTransaction(ctx, tx, model.PointTransaction{
+		UserId:       order.UserId,
+		OrderId:      &order.Id,
+		PointsChange: -points,
+		Description:  fmt.Sprintf("redeemed for order %s", order.BookingCode),
+	})
+}
+
+// AwardPoints credits the points earned with a paid order.
+func (p PointService) AwardPoints(ctx context.Context, tx pgx.Tx, order model.Order) error {
+	points := order.TotalPrice / pointEarnAmount()
+	if points == 0 {
+		return nil
+	}
+
+	return p.pointRepository.InsertPointTransaction(ctx, tx, model.PointTransaction{
+		UserId:       order.UserId,
+		OrderId:      &order.Id,
+		PointsChange: points,
+		Description:  fmt.Sprintf("earned from order %s", order.BookingCode),
+	})
+}
+
+// ReversePoints undoes every point movement recorded for the order, whether
+// points were earned with it or spent on it. Earned points that were already
+// spent elsewhere can not be taken back: the reversal is clamped at the
+// user's current balance so it never goes negative, and the points written
+// off are noted in the transaction description.
+func (p PointService) ReversePoints(ctx context.Context, tx pgx.Tx, order model.Order) error {
+	total, err := p.pointRepository.GetOrderPointsTotal(ctx, tx, order.Id)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+
+	description := fmt.Sprintf("reversal for order %s", order.BookingCode)
+	change := -total
+	if total > 0 {
+		balance, err := p.pointRepository.GetPointBalanceForUpdate(ctx, tx, order.UserId)
+		if err != nil {
+			return err
+		}
+		change = -min(total, max(balance, 0))
+		if written := total + change; written > 0 {
+			log.Printf("Reversal for order %d clamped at balance, %d points written off", order.Id, written)
+			description = fmt.Sprintf("%s (%d points already spent)", description, written)
+		}
+	}
+
+	return p.pointRepository.InsertPointTransaction(ctx, tx, model.PointTransaction{
+		UserId:       order.UserId,
+		OrderId:      &order.Id,
+		PointsChange: change,
+		Description:  description,
+	})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+type fakePointRepo struct {
+	repository.PointRepo
+	orderTotal   int
+	balance      int
+	transactions []model.PointTransaction
+}
+
+func (f *fakePointRepo) GetOrderPointsTotal(ctx context.Context, db repository.DBTX, orderId int) (int, error) {
+	return f.orderTotal, nil
+}
+
+func (f *fakePointRepo) GetPointBalanceForUpdate(ctx context.Context, db repository.DBTX, userId int) (int, error) {
+	return f.balance, nil
+}
+
+func (f *fakePointRepo) InsertPointTransaction(ctx context.Context, db repository.DBTX, trx model.PointTransaction) error {
+	f.transactions = append(f.transactions, trx)
+	f.balance += trx.PointsChange
+	return nil
+}
+
+func TestReversePoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		orderTotal  int
+		balance     int
+		wantChange  []int
+		wantBalance int
+	}{
+		{name: "nothing to reverse", orderTotal: 0, balance: 30, wantBalance: 30},
+		{name: "earned points taken back", orderTotal: 50, balance: 80, wantChange: []int{-50}, wantBalance: 30},
+		{name: "spent points clamped", orderTotal: 50, balance: 20, wantChange: []int{-20}, wantBalance: 0},
+		{name: "all points spent", orderTotal: 50, balance: 0, wantChange: []int{0}, wantBalance: 0},
+		{name: "redeemed points given back", orderTotal: -40, balance: 10, wantChange: []int{40}, wantBalance: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePointRepo{orderTotal: tt.orderTotal, balance: tt.balance}
+			p := PointService{pointRepository: repo}
+			order := model.Order{Id: 1, UserId: 2, BookingCode: "TKZ-1"}
+
+			if err := p.ReversePoints(context.Background(), nil, order); err != nil {
+				t.Fatalf("ReversePoints() error = %v", err)
+			}
+			if len(repo.transactions) != len(tt.wantChange) {
+				t.Fatalf("recorded %d transactions, want %d", len(repo.transactions), len(tt.wantChange))
+			}
+			for i, trx := range repo.transactions {
+				if trx.PointsChange != tt.wantChange[i] {
+					t.Errorf("transaction %d changes %d points, want %d", i, trx.PointsChange, tt.wantChange[i])
+				}
+			}
+			if repo.balance != tt.wantBalance {
+				t.Errorf("balance = %d, want %d", repo.balance, tt.wantBalance)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+type PricingService struct {
+	pricingRepository repository.PricingRepo
+	db                *pgxpool.Pool
+}
+
+func NewPricingService(pricingRepository repository.PricingRepo, db *pgxpool.Pool) *PricingService {
+	return &PricingService{
+		pricingRepository: pricingRepository,
+		db:                db,
+	}
+}
+
+func toSeatTypePriceResponse(p model.SeatTypePrice) dto.SeatTypePriceResponse {
+	return dto.SeatTypePriceResponse{
+		Id:         p.Id,
+		SeatType:   p.SeatType,
+		CinemaId:   p.CinemaId,
+		ScheduleId: p.ScheduleId,
+		Multiplier: p.Multiplier,
+		Surcharge:  p.Surcharge,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
+func (p PricingService) GetSeatTypePrices(ctx context.Context, cinemaId *int, scheduleId *int) ([]dto.SeatTypePriceResponse, error) {
+	prices, err := p.pricingRepository.GetSeatTypePrices(ctx, p.db, cinemaId, scheduleId)
+	if err != nil {
+		log.Println("Service Error (GetSeatTypePrices):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.SeatTypePriceResponse, 0, len(prices))
+	for _, price := range prices {
+		response = append(response, toSeatTypePriceResponse(price))
+	}
+	return response, nil
+}
+
+// SaveSeatTypePrice creates the rule for a seat type of a cinema or a
+// schedule, or replaces the existing one.
+func (p PricingService) SaveSeatTypePrice(ctx context.Context, req dto.SeatTypePriceRequest) (dto.SeatTypePriceResponse, error) {
+	if (req.CinemaId == nil) == (req.ScheduleId == nil) {
+		return dto.SeatTypePriceResponse{}, apperr.ErrInvalidPriceScope
+	}
+
+	saved, err := p.pricingRepository.UpsertSeatTypePrice(ctx, p.db, model.SeatTypePrice{
+		SeatType:   strings.ToLower(strings.TrimSpace(req.SeatType)),
+		CinemaId:   req.CinemaId,
+		ScheduleId: req.ScheduleId,
+		Multiplier: req.Multiplier,
+		Surcharge:  req.Surcharge,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return dto.SeatTypePriceResponse{}, apperr.ErrPriceScopeNotFound
+		}
+		log.Println("Service Error (SaveSeatTypePrice):", err.Error())
+		return dto.SeatTypePriceResponse{}, err
+	}
+	return toSeatTypePriceResponse(saved), nil
+}
+
+func (p PricingService) DeleteSeatTypePrice(ctx context.Context, id int) error {
+	err := p.pricingRepository.DeleteSeatTypePrice(ctx, p.db, id)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return apperr.ErrSeatTypePriceNotFound
+		}
+		log.Println("Service Error (DeleteSeatTypePrice):", err.Error())
+		return err
+	}
+	return nil
+}
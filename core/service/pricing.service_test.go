@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+type fakeScheduleOrderRepo struct {
+	repository.OrderRepo
+	basePrice int
+}
+
+func (f fakeScheduleOrderRepo) GetPriceFromSchedule(ctx context.Context, db repository.DBTX, scheduleId int) (int, error) {
+	return f.basePrice, nil
+}
+
+// fakePricingRepo returns the rules as GetSeatTypePricesForSchedule resolves
+// them: at most one per seat type, the schedule's before the cinema's.
+type fakePricingRepo struct {
+	repository.PricingRepo
+	rules map[string]model.SeatTypePrice
+}
+
+func (f fakePricingRepo) GetSeatTypePricesForSchedule(ctx context.Context, db repository.DBTX, scheduleId int) (map[string]model.SeatTypePrice, error) {
+	return f.rules, nil
+}
+
+func TestSeatPrices(t *testing.T) {
+	scheduleId := 7
+	o := OrderService{
+		orderRepository: fakeScheduleOrderRepo{basePrice: 50000},
+		pricingRepository: fakePricingRepo{rules: map[string]model.SeatTypePrice{
+			"vip":    {SeatType: "vip", ScheduleId: &scheduleId, Multiplier: 2},
+			"couple": {SeatType: "couple", Multiplier: 1, Surcharge: 15000},
+		}},
+	}
+	seats := []model.Seat{
+		{SeatId: 1, SeatType: "regular"},
+		{SeatId: 2, SeatType: "vip"},
+		{SeatId: 3, SeatType: "couple"},
+	}
+
+	prices, err := o.seatPrices(context.Background(), nil, scheduleId, seats)
+	if err != nil {
+		t.Fatalf("seatPrices() error = %v", err)
+	}
+	want := map[int]int{1: 50000, 2: 100000, 3: 65000}
+	for seatId, price := range want {
+		if prices[seatId] != price {
+			t.Errorf("price of seat %d = %d, want %d", seatId, prices[seatId], price)
+		}
+	}
+}
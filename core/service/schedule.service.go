@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/Albaihaqi354/Tickitz-BE/pkg"
+)
+
+// maxScheduleDays limits how many days one bulk creation may span.
+const maxScheduleDays = 62
+
+type ScheduleService struct {
+	scheduleRepository repository.ScheduleRepo
+	cinemaRepository   repository.CinemaRepo
+	db                 *pgxpool.Pool
+}
+
+func NewScheduleService(scheduleRepository repository.ScheduleRepo, cinemaRepository repository.CinemaRepo, db *pgxpool.Pool) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepository: scheduleRepository,
+		cinemaRepository:   cinemaRepository,
+		db:                 db,
+	}
+}
+
+// scheduleCleaningBuffer is the time an auditorium needs between the end of a show
+// and the start of the next one.
+func scheduleCleaningBuffer() time.Duration {
+	return pkg.GetEnvDuration("SCHEDULE_CLEANING_BUFFER", 15*time.Minute)
+}
+
+func toScheduleResponse(s model.Schedule) dto.ScheduleResponse {
+	return dto.ScheduleResponse{
+		Id:             s.Id,
+		MovieId:        s.MovieId,
+		MovieTitle:     s.MovieTitle,
+		CinemaId:       s.CinemaId,
+		CinemaName:     s.CinemaName,
+		AuditoriumId:   s.AuditoriumId,
+		AuditoriumName: s.AuditoriumName,
+		ShowDate:       s.ShowDate,
+		ShowTime:       s.ShowTime,
+		StartsAt:       s.StartsAt,
+		EndsAt:         s.EndsAt,
+		Price:          s.Price,
+		PaidOrders:     s.PaidOrders,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+// showStart parses a show date and time in the cinema's local time. The
+// result is in UTC so it is stored with the same wall clock.
+func showStart(date string, clock string) (time.Time, error) {
+	startsAt, err := time.Parse("2006-01-02 15:04", date+" "+clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", apperr.ErrInvalidSchedule, err.Error())
+	}
+	return startsAt, nil
+}
+
+func (s ScheduleService) GetSchedules(ctx context.Context, filter model.ScheduleFilter) ([]dto.ScheduleResponse, error) {
+	schedules, err := s.scheduleRepository.GetSchedules(ctx, s.db, filter)
+	if err != nil {
+		log.Println("Service Error (GetSchedules):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.ScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		response = append(response, toScheduleResponse(schedule))
+	}
+	return response, nil
+}
+
+func (s ScheduleService) GetSchedule(ctx context.Context, id int) (dto.ScheduleResponse, error) {
+	schedule, err := s.scheduleRepository.GetScheduleById(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ScheduleResponse{}, apperr.ErrScheduleNotFound
+		}
+		log.Println("Service Error (GetSchedule):", err.Error())
+		return dto.ScheduleResponse{}, err
+	}
+	return toScheduleResponse(schedule), nil
+}
+
+// CreateSchedules creates a show at every time of req.Times on every day of
+// the date range. Either all shows are created or, when one of them overlaps
+// another show in the auditorium, none.
+func (s ScheduleService) CreateSchedules(ctx context.Context, req dto.CreateSchedulesRequest) ([]dto.ScheduleResponse, error) {
+	firstDay, err := time.Parse(time.DateOnly, req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", apperr.ErrInvalidSchedule, err.Error())
+	}
+	lastDay, err := time.Parse(time.DateOnly, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", apperr.ErrInvalidSchedule, err.Error())
+	}
+	if lastDay.Before(firstDay) {
+		return nil, fmt.Errorf("%w: end_date is before start_date", apperr.ErrInvalidSchedule)
+	}
+	if days := int(lastDay.Sub(firstDay).Hours()/24) + 1; days > maxScheduleDays {
+		return nil, fmt.Errorf("%w: date range can not exceed %d days", apperr.ErrInvalidSchedule, maxScheduleDays)
+	}
+
+	var starts []time.Time
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		for _, clock := range req.Times {
+			startsAt, err := showStart(day.Format(time.DateOnly), clock)
+			if err != nil {
+				return nil, err
+			}
+			starts = append(starts, startsAt)
+		}
+	}
+	slices.SortFunc(starts, func(a, b time.Time) int { return a.Compare(b) })
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (CreateSchedules):", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Schedule writes for an auditorium are serialized on the auditorium row,
+	// so two admins can not create overlapping shows at the same time.
+	auditorium, err := s.lockAuditorium(ctx, tx, req.AuditoriumId)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := s.scheduleRepository.GetMovieDuration(ctx, tx, req.MovieId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.ErrMovieNotFound
+		}
+		return nil, err
+	}
+
+	length := time.Duration(duration)*time.Minute + scheduleCleaningBuffer()
+	for i, startsAt := range starts {
+		if i > 0 && startsAt.Before(starts[i-1].Add(length)) {
+			return nil, &apperr.ScheduleOverlapError{StartsAt: startsAt, OtherStartsAt: starts[i-1]}
+		}
+		if err := s.checkOverlap(ctx, tx, auditorium.Id, startsAt, length, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]int, 0, len(starts))
+	for _, startsAt := range starts {
+		id, err := s.scheduleRepository.InsertSchedule(ctx, tx, model.Schedule{
+			MovieId:      req.MovieId,
+			CinemaId:     auditorium.CinemaId,
+			AuditoriumId: auditorium.Id,
+			StartsAt:     startsAt,
+			Price:        req.Price,
+		})
+		if err != nil {
+			log.Println("Service Error (CreateSchedules):", err.Error())
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (CreateSchedules):", err.Error())
+		return nil, err
+	}
+	return s.GetSchedules(ctx, model.ScheduleFilter{Ids: ids})
+}
+
+// UpdateSchedule replaces the movie, auditorium, time and price of a schedule
+// that has no paid orders. While it has pending orders the movie and
+// auditorium stay fixed, see checkScheduleChange.
+func (s ScheduleService) UpdateSchedule(ctx context.Context, id int, req dto.UpdateScheduleRequest) (dto.ScheduleResponse, error) {
+	startsAt, err := showStart(req.ShowDate, req.ShowTime)
+	if err != nil {
+		return dto.ScheduleResponse{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (UpdateSchedule):", err.Error())
+		return dto.ScheduleResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, orders, err := s.lockUnpaidSchedule(ctx, tx, id)
+	if err != nil {
+		return dto.ScheduleResponse{}, err
+	}
+	if err := checkScheduleChange(current, req.MovieId, req.AuditoriumId, orders); err != nil {
+		return dto.ScheduleResponse{}, err
+	}
+	auditorium, err := s.lockAuditorium(ctx, tx, req.AuditoriumId)
+	if err != nil {
+		return dto.ScheduleResponse{}, err
+	}
+	duration, err := s.scheduleRepository.GetMovieDuration(ctx, tx, req.MovieId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ScheduleResponse{}, apperr.ErrMovieNotFound
+		}
+		return dto.ScheduleResponse{}, err
+	}
+
+	length := time.Duration(duration)*time.Minute + scheduleCleaningBuffer()
+	if err := s.checkOverlap(ctx, tx, auditorium.Id, startsAt, length, id); err != nil {
+		return dto.ScheduleResponse{}, err
+	}
+
+	err = s.scheduleRepository.UpdateSchedule(ctx, tx, model.Schedule{
+		Id:           id,
+		MovieId:      req.MovieId,
+		CinemaId:     auditorium.CinemaId,
+		AuditoriumId: auditorium.Id,
+		StartsAt:     startsAt,
+		Price:        req.Price,
+	})
+	if err != nil {
+		log.Println("Service Error (UpdateSchedule):", err.Error())
+		return dto.ScheduleResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (UpdateSchedule):", err.Error())
+		return dto.ScheduleResponse{}, err
+	}
+	return s.GetSchedule(ctx, id)
+}
+
+// DeleteSchedule deletes a schedule that has no paid or pending orders. Its
+// ended orders are deleted with it. Pending orders are not cancelled here:
+// that has to go through the order service so their points, vouchers,
+// payments and seat holds are released, so the delete waits until they are
+// paid or expired.
+func (s ScheduleService) DeleteSchedule(ctx context.Context, id int) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (DeleteSchedule):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, orders, err := s.lockUnpaidSchedule(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if orders.Pending > 0 {
+		return fmt.Errorf("%w: %d pending orders", apperr.ErrScheduleHasPendingOrders, orders.Pending)
+	}
+	if err := s.scheduleRepository.DeleteSchedule(ctx, tx, id); err != nil {
+		log.Println("Service Error (DeleteSchedule):", err.Error())
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// lockUnpaidSchedule locks the schedule and its orders, fails when any of
+// the orders is paid, and returns the schedule with its order counts.
+func (s ScheduleService) lockUnpaidSchedule(ctx context.Context, tx pgx.Tx, id int) (model.Schedule, model.ScheduleOrderCounts, error) {
+	schedule, err := s.scheduleRepository.LockSchedule(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Schedule{}, model.ScheduleOrderCounts{}, apperr.ErrScheduleNotFound
+		}
+		return model.Schedule{}, model.ScheduleOrderCounts{}, err
+	}
+	orders, err := s.scheduleRepository.CountOrdersForUpdate(ctx, tx, id)
+	if err != nil {
+		return model.Schedule{}, model.ScheduleOrderCounts{}, err
+	}
+	if orders.Paid > 0 {
+		return model.Schedule{}, model.ScheduleOrderCounts{}, fmt.Errorf("%w: %d paid orders", apperr.ErrScheduleHasPaidOrders, orders.Paid)
+	}
+	return schedule, orders, nil
+}
+
+// checkScheduleChange refuses to move a schedule with pending orders to
+// another movie or auditorium: those orders hold seats of the current
+// auditorium and can still be paid and checked in. Time and price may change.
+func checkScheduleChange(current model.Schedule, movieId int, auditoriumId int, orders model.ScheduleOrderCounts) error {
+	if orders.Pending == 0 {
+		return nil
+	}
+	if current.MovieId != movieId || current.AuditoriumId != auditoriumId {
+		return fmt.Errorf("%w: %d pending orders, the movie and auditorium can not change", apperr.ErrScheduleHasPendingOrders, orders.Pending)
+	}
+	return nil
+}
+
+// lockAuditorium locks the auditorium a schedule is written to.
+func (s ScheduleService) lockAuditorium(ctx context.Context, tx pgx.Tx, id int) (model.Auditorium, error) {
+	auditorium, err := s.cinemaRepository.GetAuditoriumForUpdate(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Auditorium{}, apperr.ErrAuditoriumNotFound
+		}
+		return model.Auditorium{}, err
+	}
+	return auditorium, nil
+}
+
+// checkOverlap fails when a show starting at startsAt and taking length,
+// cleaning buffer included, overlaps another show in the auditorium.
+func (s ScheduleService) checkOverlap(ctx context.Context, tx pgx.Tx, auditoriumId int, startsAt time.Time, length time.Duration, excludeId int) error {
+	bufferMinutes := int(scheduleCleaningBuffer().Minutes())
+	other, err := s.scheduleRepository.FindOverlap(ctx, tx, auditoriumId, startsAt, startsAt.Add(length), bufferMinutes, excludeId)
+	if err != nil {
+		return err
+	}
+	if other != nil {
+		return &apperr.ScheduleOverlapError{
+			StartsAt:      startsAt,
+			ScheduleId:    other.Id,
+			Movie:         other.MovieTitle,
+			OtherStartsAt: other.StartsAt,
+		}
+	}
+	return nil
+}
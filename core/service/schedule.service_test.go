@@ -0,0 +1,37 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+)
+
+func TestCheckScheduleChange(t *testing.T) {
+	current := model.Schedule{Id: 1, MovieId: 10, AuditoriumId: 20}
+	pending := model.ScheduleOrderCounts{Pending: 2}
+
+	tests := []struct {
+		name         string
+		movieId      int
+		auditoriumId int
+		orders       model.ScheduleOrderCounts
+		wantErr      error
+	}{
+		{"no orders, new auditorium", 10, 21, model.ScheduleOrderCounts{}, nil},
+		{"no orders, new movie", 11, 20, model.ScheduleOrderCounts{}, nil},
+		{"pending orders, same movie and auditorium", 10, 20, pending, nil},
+		{"pending orders, new auditorium", 10, 21, pending, apperr.ErrScheduleHasPendingOrders},
+		{"pending orders, new movie", 11, 20, pending, apperr.ErrScheduleHasPendingOrders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkScheduleChange(current, tt.movieId, tt.auditoriumId, tt.orders)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkScheduleChange() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
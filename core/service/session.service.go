@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+// SessionService tracks the logins of users. A session lives in Postgres
+// next to its refresh token family; the access tokens issued for it and its
+// latest activity live in Redis.
+type SessionService struct {
+	sessionRepository      repository.SessionRepo
+	refreshTokenRepository repository.RefreshTokenRepo
+	authRepository         *repository.AuthRepository
+	db                     *pgxpool.Pool
+}
+
+func NewSessionService(sessionRepository repository.SessionRepo, refreshTokenRepository repository.RefreshTokenRepo, authRepository *repository.AuthRepository, db *pgxpool.Pool) *SessionService {
+	return &SessionService{
+		sessionRepository:      sessionRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		authRepository:         authRepository,
+		db:                     db,
+	}
+}
+
+// StartSession records a login whose refresh tokens belong to familyId.
+func (s SessionService) StartSession(ctx context.Context, tx pgx.Tx, userId int, familyId string, userAgent string, ipAddress string, expiresAt time.Time) (int, error) {
+	return s.sessionRepository.InsertSession(ctx, tx, model.Session{
+		UserId:    userId,
+		FamilyId:  familyId,
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// TouchSession records a token refresh of the session of familyId. Returns
+// pgx.ErrNoRows when the session has been revoked.
+func (s SessionService) TouchSession(ctx context.Context, tx pgx.Tx, familyId string, ipAddress string, expiresAt time.Time) (int, error) {
+	return s.sessionRepository.TouchSession(ctx, tx, familyId, ipAddress, expiresAt)
+}
+
+// RevokeFamily revokes a refresh token family and its session. It returns
+// the id of the session, or 0 when it was already revoked; once tx commits,
+// its access tokens have to be dropped with ClearSessionTokens.
+func (s SessionService) RevokeFamily(ctx context.Context, tx pgx.Tx, familyId string) (int, error) {
+	if err := s.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, familyId); err != nil {
+		return 0, err
+	}
+	return s.sessionRepository.RevokeSessionByFamily(ctx, tx, familyId)
+}
+
+// ClearSessionTokens removes the access tokens of a revoked session from the
+// whitelist.
+func (s SessionService) ClearSessionTokens(ctx context.Context, sessionId int) error {
+	if sessionId == 0 {
+		return nil
+	}
+	if err := s.authRepository.RevokeSessionTokens(ctx, sessionId); err != nil {
+		log.Println("Service Error (ClearSessionTokens):", err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetSessions lists the active sessions of the user, marking the one of the
+// current request.
+func (s SessionService) GetSessions(ctx context.Context, userId int, currentSessionId int) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepository.GetActiveSessions(ctx, s.db, userId)
+	if err != nil {
+		log.Println("Service Error (GetSessions):", err.Error())
+		return nil, err
+	}
+
+	ids := make([]int, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.Id
+	}
+	activity, err := s.authRepository.GetSessionActivity(ctx, ids)
+	if err != nil {
+		// The stored last seen time is only less precise.
+		log.Println("Service Error (GetSessions):", err.Error())
+		activity = map[int]model.SessionActivity{}
+	}
+
+	response := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		res := dto.SessionResponse{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			LastSeenAt: session.LastSeenAt,
+			CreatedAt:  session.CreatedAt,
+			Current:    session.Id == currentSessionId,
+		}
+		if a, ok := activity[session.Id]; ok && a.LastSeenAt.After(res.LastSeenAt) {
+			res.LastSeenAt = a.LastSeenAt
+			if a.IpAddress != "" {
+				res.IpAddress = a.IpAddress
+			}
+		}
+		response = append(response, res)
+	}
+	return response, nil
+}
+
+// RevokeSession logs one session of the user out.
+func (s SessionService) RevokeSession(ctx context.Context, userId int, sessionId int) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (RevokeSession):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	session, err := s.sessionRepository.RevokeSession(ctx, tx, userId, sessionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.ErrSessionNotFound
+		}
+		return err
+	}
+	if err := s.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, session.FamilyId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (RevokeSession):", err.Error())
+		return err
+	}
+
+	return s.ClearSessionTokens(ctx, session.Id)
+}
+
+// RevokeAllSessions logs the user out everywhere, the current session
+// included.
+func (s SessionService) RevokeAllSessions(ctx context.Context, userId int) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		log.Println("Service Error (RevokeAllSessions):", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sessions, err := s.sessionRepository.RevokeUserSessions(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepository.RevokeUserRefreshTokens(ctx, tx, userId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Service Error (RevokeAllSessions):", err.Error())
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := s.ClearSessionTokens(ctx, session.Id); err != nil {
+			return err
+		}
+	}
+	// Also covers access tokens issued before sessions were tracked.
+	if err := s.authRepository.RevokeUserTokens(ctx, userId); err != nil {
+		log.Println("Service Error (RevokeAllSessions):", err.Error())
+		return err
+	}
+	return nil
+}
@@ -12,11 +12,13 @@ import (
 
 type UserService struct {
 	userRepository *repository.UserRepository
+	sessionService *SessionService
 }
 
-func NewUserService(userRepository *repository.UserRepository) *UserService {
+func NewUserService(userRepository *repository.UserRepository, sessionService *SessionService) *UserService {
 	return &UserService{
 		userRepository: userRepository,
+		sessionService: sessionService,
 	}
 }
 
@@ -103,6 +105,13 @@ func (u UserService) UpdatePassword(ctx context.Context, userId int, req dto.Upd
 		return errors.New("internal server error")
 	}
 
+	// Whoever knew the old password is logged out, this device included.
+	err = u.sessionService.RevokeAllSessions(ctx, userId)
+	if err != nil {
+		log.Println("Error revoking sessions:", err.Error())
+		return errors.New("internal server error")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+)
+
+type VoucherService struct {
+	voucherRepository repository.VoucherRepo
+	db                *pgxpool.Pool
+}
+
+func NewVoucherService(voucherRepository repository.VoucherRepo, db *pgxpool.Pool) *VoucherService {
+	return &VoucherService{
+		voucherRepository: voucherRepository,
+		db:                db,
+	}
+}
+
+func toVoucherResponse(v model.Voucher) dto.VoucherResponse {
+	return dto.VoucherResponse{
+		Id:            v.Id,
+		Code:          v.Code,
+		Description:   v.Description,
+		DiscountType:  v.DiscountType,
+		DiscountValue: v.DiscountValue,
+		MaxDiscount:   v.MaxDiscount,
+		MinPurchase:   v.MinPurchase,
+		StartsAt:      v.StartsAt,
+		EndsAt:        v.EndsAt,
+		UsageLimit:    v.UsageLimit,
+		PerUserLimit:  v.PerUserLimit,
+		MovieId:       v.MovieId,
+		CinemaId:      v.CinemaId,
+		CityId:        v.CityId,
+		IsActive:      v.IsActive,
+		CreatedAt:     v.CreatedAt,
+		UpdatedAt:     v.UpdatedAt,
+	}
+}
+
+func voucherFromRequest(req dto.VoucherRequest) (model.Voucher, error) {
+	if req.DiscountType == model.VoucherTypePercentage && req.DiscountValue > 100 {
+		return model.Voucher{}, fmt.Errorf("%w: percentage discount can not exceed 100", apperr.ErrInvalidVoucherRule)
+	}
+	if !req.StartsAt.Before(req.EndsAt) {
+		return model.Voucher{}, fmt.Errorf("%w: starts_at must be before ends_at", apperr.ErrInvalidVoucherRule)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	return model.Voucher{
+		Code:          strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description:   req.Description,
+		DiscountType:  req.DiscountType,
+		DiscountValue: req.DiscountValue,
+		MaxDiscount:   req.MaxDiscount,
+		MinPurchase:   req.MinPurchase,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+		UsageLimit:    req.UsageLimit,
+		PerUserLimit:  req.PerUserLimit,
+		MovieId:       req.MovieId,
+		CinemaId:      req.CinemaId,
+		CityId:        req.CityId,
+		IsActive:      isActive,
+	}, nil
+}
+
+// voucherWriteError turns constraint violations into errors the admin can
+// act on.
+func voucherWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return apperr.ErrVoucherCodeTaken
+		case "23503":
+			return fmt.Errorf("%w: movie, cinema or city not found", apperr.ErrInvalidVoucherRule)
+		case "23514":
+			return apperr.ErrInvalidVoucherRule
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.ErrVoucherNotFound
+	}
+	return err
+}
+
+func (v VoucherService) GetVouchers(ctx context.Context) ([]dto.VoucherResponse, error) {
+	vouchers, err := v.voucherRepository.GetVouchers(ctx, v.db)
+	if err != nil {
+		log.Println("Service Error (GetVouchers):", err.Error())
+		return nil, err
+	}
+
+	response := make([]dto.VoucherResponse, 0, len(vouchers))
+	for _, voucher := range vouchers {
+		response = append(response, toVoucherResponse(voucher))
+	}
+	return response, nil
+}
+
+func (v VoucherService) GetVoucher(ctx context.Context, id int) (dto.VoucherResponse, error) {
+	voucher, err := v.voucherRepository.GetVoucherById(ctx, v.db, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.VoucherResponse{}, apperr.ErrVoucherNotFound
+		}
+		return dto.VoucherResponse{}, err
+	}
+	return toVoucherResponse(voucher), nil
+}
+
+func (v VoucherService) CreateVoucher(ctx context.Context, req dto.VoucherRequest) (dto.VoucherResponse, error) {
+	voucher, err := voucherFromRequest(req)
+	if err != nil {
+		return dto.VoucherResponse{}, err
+	}
+
+	saved, err := v.voucherRepository.InsertVoucher(ctx, v.db, voucher)
+	if err != nil {
+		log.Println("Service Error (CreateVoucher):", err.Error())
+		return dto.VoucherResponse{}, voucherWriteError(err)
+	}
+	return toVoucherResponse(saved), nil
+}
+
+func (v VoucherService) UpdateVoucher(ctx context.Context, id int, req dto.VoucherRequest) (dto.VoucherResponse, error) {
+	voucher, err := voucherFromRequest(req)
+	if err != nil {
+		return dto.VoucherResponse{}, err
+	}
+	voucher.Id = id
+
+	saved, err := v.voucherRepository.UpdateVoucher(ctx, v.db, voucher)
+	if err != nil {
+		log.Println("Service Error (UpdateVoucher):", err.Error())
+		return dto.VoucherResponse{}, voucherWriteError(err)
+	}
+	return toVoucherResponse(saved), nil
+}
+
+func (v VoucherService) DeleteVoucher(ctx context.Context, id int) error {
+	err := v.voucherRepository.DeleteVoucher(ctx, v.db, id)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNoRowsUpdated) {
+			return apperr.ErrVoucherNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperr.ErrVoucherInUse
+		}
+		log.Println("Service Error (DeleteVoucher):", err.Error())
+		return err
+	}
+	return nil
+}
+
+// ReserveVoucher checks a promo code for an order of the user on the schedule
+// and returns the voucher with the discount for subtotal. It must run in the
+// checkout transaction: the voucher row stays locked until the usage is
+// recorded with RecordUsage, so usage limits hold under concurrency.
+func (v VoucherService) ReserveVoucher(ctx context.Context, tx pgx.Tx, code string, userId int, scheduleId int, subtotal int) (model.Voucher, int, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	voucher, err := v.voucherRepository.GetVoucherByCodeForUpdate(ctx, tx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "code not found"}
+		}
+		return model.Voucher{}, 0, err
+	}
+	if !voucher.IsActive || !voucher.IsCurrent {
+		return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "code is not active"}
+	}
+	if subtotal < voucher.MinPurchase {
+		return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: fmt.Sprintf("minimum purchase is %d", voucher.MinPurchase)}
+	}
+
+	if voucher.MovieId != nil || voucher.CinemaId != nil || voucher.CityId != nil {
+		scope, err := v.voucherRepository.GetScheduleScope(ctx, tx, scheduleId)
+		if err != nil {
+			return model.Voucher{}, 0, err
+		}
+		switch {
+		case voucher.MovieId != nil && *voucher.MovieId != scope.MovieId:
+			return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "code is not valid for this movie"}
+		case voucher.CinemaId != nil && *voucher.CinemaId != scope.CinemaId:
+			return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "code is not valid for this cinema"}
+		case voucher.CityId != nil && *voucher.CityId != scope.CityId:
+			return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "code is not valid in this city"}
+		}
+	}
+
+	if voucher.UsageLimit != nil || voucher.PerUserLimit != nil {
+		total, byUser, err := v.voucherRepository.CountVoucherUsages(ctx, tx, voucher.Id, userId)
+		if err != nil {
+			return model.Voucher{}, 0, err
+		}
+		if voucher.UsageLimit != nil && total >= *voucher.UsageLimit {
+			return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "code has been fully redeemed"}
+		}
+		if voucher.PerUserLimit != nil && byUser >= *voucher.PerUserLimit {
+			return model.Voucher{}, 0, &apperr.VoucherError{Code: code, Reason: "you have already used this code"}
+		}
+	}
+
+	return voucher, voucher.Discount(subtotal), nil
+}
+
+func (v VoucherService) RecordUsage(ctx context.Context, tx pgx.Tx, voucher model.Voucher, userId int, orderId int, discount int) error {
+	return v.voucherRepository.InsertVoucherUsage(ctx, tx, model.VoucherUsage{
+		VoucherId: voucher.Id,
+		UserId:    userId,
+		OrderId:   orderId,
+		Discount:  discount,
+	})
+}
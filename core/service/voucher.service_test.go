@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/dto"
+	apperr "github.com/Albaihaqi354/Tickitz-BE/core/err"
+	"github.com/Albaihaqi354/Tickitz-BE/core/model"
+	"github.com/Albaihaqi354/Tickitz-BE/core/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+type fakeVoucherRepo struct {
+	repository.VoucherRepo
+	voucher      *model.Voucher
+	scope        model.ScheduleScope
+	usages       int
+	usagesByUser int
+}
+
+func (f fakeVoucherRepo) GetVoucherByCodeForUpdate(ctx context.Context, db repository.DBTX, code string) (model.Voucher, error) {
+	if f.voucher == nil || f.voucher.Code != code {
+		return model.Voucher{}, pgx.ErrNoRows
+	}
+	return *f.voucher, nil
+}
+
+func (f fakeVoucherRepo) GetScheduleScope(ctx context.Context, db repository.DBTX, scheduleId int) (model.ScheduleScope, error) {
+	return f.scope, nil
+}
+
+func (f fakeVoucherRepo) CountVoucherUsages(ctx context.Context, db repository.DBTX, voucherId int, userId int) (int, int, error) {
+	return f.usages, f.usagesByUser, nil
+}
+
+func TestReserveVoucher(t *testing.T) {
+	one, two, otherId := 1, 2, 99
+	base := model.Voucher{
+		Id:            1,
+		Code:          "HEMAT10",
+		DiscountType:  model.VoucherTypePercentage,
+		DiscountValue: 10,
+		MinPurchase:   50000,
+		IsActive:      true,
+		IsCurrent:     true,
+	}
+	with := func(change func(v *model.Voucher)) *model.Voucher {
+		v := base
+		change(&v)
+		return &v
+	}
+	scope := model.ScheduleScope{MovieId: 1, CinemaId: 2, CityId: 3}
+
+	tests := []struct {
+		name     string
+		repo     fakeVoucherRepo
+		code     string
+		subtotal int
+		want     int
+		reason   string
+	}{
+		{name: "applies", repo: fakeVoucherRepo{voucher: &base}, code: "HEMAT10", subtotal: 100000, want: 10000},
+		{name: "code is normalised", repo: fakeVoucherRepo{voucher: &base}, code: " hemat10 ", subtotal: 100000, want: 10000},
+		{name: "unknown code", repo: fakeVoucherRepo{voucher: &base}, code: "NOPE", subtotal: 100000, reason: "code not found"},
+		{name: "inactive", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.IsActive = false })}, code: "HEMAT10", subtotal: 100000, reason: "not active"},
+		{name: "outside its period", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.IsCurrent = false })}, code: "HEMAT10", subtotal: 100000, reason: "not active"},
+		{name: "below minimum purchase", repo: fakeVoucherRepo{voucher: &base}, code: "HEMAT10", subtotal: 49999, reason: "minimum purchase"},
+		{name: "matching scope", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.MovieId = &one; v.CinemaId = &two }), scope: scope}, code: "HEMAT10", subtotal: 100000, want: 10000},
+		{name: "other movie", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.MovieId = &otherId }), scope: scope}, code: "HEMAT10", subtotal: 100000, reason: "this movie"},
+		{name: "other cinema", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.CinemaId = &otherId }), scope: scope}, code: "HEMAT10", subtotal: 100000, reason: "this cinema"},
+		{name: "other city", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.CityId = &otherId }), scope: scope}, code: "HEMAT10", subtotal: 100000, reason: "this city"},
+		{name: "under usage limit", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.UsageLimit = &two }), usages: 1}, code: "HEMAT10", subtotal: 100000, want: 10000},
+		{name: "usage limit reached", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.UsageLimit = &two }), usages: 2}, code: "HEMAT10", subtotal: 100000, reason: "fully redeemed"},
+		{name: "per user limit reached", repo: fakeVoucherRepo{voucher: with(func(v *model.Voucher) { v.PerUserLimit = &one }), usages: 5, usagesByUser: 1}, code: "HEMAT10", subtotal: 100000, reason: "already used"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := VoucherService{voucherRepository: tt.repo}
+			voucher, discount, err := v.ReserveVoucher(context.Background(), nil, tt.code, 10, 20, tt.subtotal)
+
+			if tt.reason != "" {
+				var voucherErr *apperr.VoucherError
+				if !errors.As(err, &voucherErr) || !strings.Contains(voucherErr.Reason, tt.reason) {
+					t.Fatalf("ReserveVoucher() error = %v, want reason containing %q", err, tt.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReserveVoucher() error = %v", err)
+			}
+			if voucher.Id != base.Id || discount != tt.want {
+				t.Errorf("ReserveVoucher() = voucher %d, discount %d, want voucher %d, discount %d", voucher.Id, discount, base.Id, tt.want)
+			}
+		})
+	}
+}
+
+func TestVoucherFromRequest(t *testing.T) {
+	now := time.Now()
+	valid := dto.VoucherRequest{
+		Code:          " hemat10 ",
+		DiscountType:  model.VoucherTypePercentage,
+		DiscountValue: 10,
+		StartsAt:      now,
+		EndsAt:        now.Add(24 * time.Hour),
+	}
+
+	voucher, err := voucherFromRequest(valid)
+	if err != nil {
+		t.Fatalf("voucherFromRequest() error = %v", err)
+	}
+	if voucher.Code != "HEMAT10" || !voucher.IsActive {
+		t.Errorf("voucherFromRequest() = code %q, active %v, want HEMAT10, active", voucher.Code, voucher.IsActive)
+	}
+
+	overHundred := valid
+	overHundred.DiscountValue = 101
+	backwards := valid
+	backwards.EndsAt = now
+	for name, req := range map[string]dto.VoucherRequest{"percentage over 100": overHundred, "ends before it starts": backwards} {
+		if _, err := voucherFromRequest(req); !errors.Is(err, apperr.ErrInvalidVoucherRule) {
+			t.Errorf("%s: voucherFromRequest() error = %v, want %v", name, err, apperr.ErrInvalidVoucherRule)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Albaihaqi354/Tickitz-BE/core/service"
+)
+
+type OrderExpiryWorker struct {
+	orderService *service.OrderService
+	interval     time.Duration
+}
+
+func NewOrderExpiryWorker(orderService *service.OrderService, interval time.Duration) *OrderExpiryWorker {
+	return &OrderExpiryWorker{
+		orderService: orderService,
+		interval:     interval,
+	}
+}
+
+// Run expires stale pending orders and retries failed refunds every interval
+// until ctx is cancelled.
+func (w *OrderExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("Order expiry worker started, interval:", w.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Order expiry worker stopped")
+			return
+		case <-ticker.C:
+			expired, err := w.orderService.ExpirePendingOrders(ctx)
+			if err != nil {
+				log.Println("Order expiry worker error:", err.Error())
+			} else if expired > 0 {
+				log.Println("Order expiry worker expired orders:", expired)
+			}
+
+			refunded, err := w.orderService.RetryRefunds(ctx)
+			if err != nil {
+				log.Println("Order expiry worker error:", err.Error())
+			} else if refunded > 0 {
+				log.Println("Order expiry worker refunded payments:", refunded)
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS orders_sync_order_details ON public.orders;
+DROP FUNCTION IF EXISTS public.orders_sync_order_details();
+DROP TRIGGER IF EXISTS order_details_fill_from_order ON public.order_details;
+DROP FUNCTION IF EXISTS public.order_details_fill_from_order();
+DROP INDEX IF EXISTS public.order_details_active_seat_key;
+ALTER TABLE public.order_details
+    DROP COLUMN IF EXISTS is_active,
+    DROP COLUMN IF EXISTS schedule_id;
@@ -0,0 +1,45 @@
+ALTER TABLE public.order_details
+    ADD COLUMN schedule_id integer,
+    ADD COLUMN is_active boolean DEFAULT true NOT NULL;
+
+UPDATE public.order_details od
+SET schedule_id = o.schedule_id,
+    is_active = COALESCE(o.payment_status, 'pending') NOT IN ('cancelled', 'expired', 'failed', 'refunded')
+FROM public.orders o
+WHERE o.id = od.order_id;
+
+ALTER TABLE public.order_details ALTER COLUMN schedule_id SET NOT NULL;
+
+ALTER TABLE ONLY public.order_details
+    ADD CONSTRAINT order_details_schedule_id_fkey FOREIGN KEY (schedule_id) REFERENCES public.schedules(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX order_details_active_seat_key ON public.order_details (schedule_id, seat_id) WHERE is_active;
+
+CREATE FUNCTION public.order_details_fill_from_order() RETURNS trigger AS $$
+BEGIN
+    SELECT o.schedule_id, COALESCE(o.payment_status, 'pending') NOT IN ('cancelled', 'expired', 'failed', 'refunded')
+    INTO NEW.schedule_id, NEW.is_active
+    FROM public.orders o
+    WHERE o.id = NEW.order_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_details_fill_from_order
+    BEFORE INSERT ON public.order_details
+    FOR EACH ROW EXECUTE FUNCTION public.order_details_fill_from_order();
+
+CREATE FUNCTION public.orders_sync_order_details() RETURNS trigger AS $$
+BEGIN
+    UPDATE public.order_details
+    SET is_active = COALESCE(NEW.payment_status, 'pending') NOT IN ('cancelled', 'expired', 'failed', 'refunded')
+    WHERE order_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_sync_order_details
+    AFTER UPDATE OF payment_status ON public.orders
+    FOR EACH ROW
+    WHEN (OLD.payment_status IS DISTINCT FROM NEW.payment_status)
+    EXECUTE FUNCTION public.orders_sync_order_details();
@@ -0,0 +1 @@
+DROP TABLE order_status_histories
\ No newline at end of file
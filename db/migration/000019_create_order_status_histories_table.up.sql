@@ -0,0 +1,30 @@
+CREATE TABLE public.order_status_histories (
+    id integer NOT NULL,
+    order_id integer NOT NULL,
+    from_status character varying NOT NULL,
+    to_status character varying NOT NULL,
+    source character varying NOT NULL,
+    changed_by integer,
+    note character varying DEFAULT ''::character varying,
+    created_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE public.order_status_histories ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.order_status_histories_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+ALTER TABLE ONLY public.order_status_histories
+    ADD CONSTRAINT order_status_histories_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.order_status_histories
+    ADD CONSTRAINT order_status_histories_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.orders(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.order_status_histories
+    ADD CONSTRAINT order_status_histories_changed_by_fkey FOREIGN KEY (changed_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+CREATE INDEX order_status_histories_order_id_idx ON public.order_status_histories (order_id);
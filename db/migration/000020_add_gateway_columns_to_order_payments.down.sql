@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS public.order_payments_order_id_idx;
+ALTER TABLE public.order_payments
+    DROP CONSTRAINT IF EXISTS order_payments_reference_key,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS payload,
+    DROP COLUMN IF EXISTS payment_url,
+    DROP COLUMN IF EXISTS amount,
+    DROP COLUMN IF EXISTS reference,
+    DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,12 @@
+ALTER TABLE public.order_payments
+    ADD COLUMN provider character varying,
+    ADD COLUMN reference character varying,
+    ADD COLUMN amount numeric,
+    ADD COLUMN payment_url character varying,
+    ADD COLUMN payload text,
+    ADD COLUMN updated_at timestamp without time zone DEFAULT now();
+
+ALTER TABLE ONLY public.order_payments
+    ADD CONSTRAINT order_payments_reference_key UNIQUE (reference);
+
+CREATE INDEX order_payments_order_id_idx ON public.order_payments (order_id);
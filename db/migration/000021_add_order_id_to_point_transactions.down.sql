@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS public.point_transactions_order_id_idx;
+ALTER TABLE public.point_transactions
+    DROP CONSTRAINT IF EXISTS point_transactions_order_id_fkey,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS order_id;
@@ -0,0 +1,8 @@
+ALTER TABLE public.point_transactions
+    ADD COLUMN order_id integer,
+    ADD COLUMN description character varying DEFAULT ''::character varying;
+
+ALTER TABLE ONLY public.point_transactions
+    ADD CONSTRAINT point_transactions_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.orders(id) ON DELETE SET NULL;
+
+CREATE INDEX point_transactions_order_id_idx ON public.point_transactions (order_id);
@@ -0,0 +1,4 @@
+ALTER TABLE public.order_details
+    DROP CONSTRAINT IF EXISTS order_details_admitted_by_fkey,
+    DROP COLUMN IF EXISTS admitted_by,
+    DROP COLUMN IF EXISTS admitted_at;
@@ -0,0 +1,6 @@
+ALTER TABLE public.order_details
+    ADD COLUMN admitted_at timestamp without time zone,
+    ADD COLUMN admitted_by integer;
+
+ALTER TABLE ONLY public.order_details
+    ADD CONSTRAINT order_details_admitted_by_fkey FOREIGN KEY (admitted_by) REFERENCES public.users(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE public.order_details
+    DROP COLUMN IF EXISTS price;
+
+DROP TABLE seat_type_prices
@@ -0,0 +1,40 @@
+CREATE TABLE public.seat_type_prices (
+    id integer NOT NULL,
+    seat_type character varying NOT NULL,
+    cinema_id integer,
+    schedule_id integer,
+    multiplier numeric DEFAULT 1 NOT NULL,
+    surcharge numeric DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    CONSTRAINT seat_type_prices_scope_check CHECK ((cinema_id IS NULL) <> (schedule_id IS NULL)),
+    CONSTRAINT seat_type_prices_multiplier_check CHECK (multiplier > 0),
+    CONSTRAINT seat_type_prices_surcharge_check CHECK (surcharge >= 0)
+);
+
+ALTER TABLE public.seat_type_prices ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.seat_type_prices_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+ALTER TABLE ONLY public.seat_type_prices
+    ADD CONSTRAINT seat_type_prices_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.seat_type_prices
+    ADD CONSTRAINT seat_type_prices_cinema_id_fkey FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.seat_type_prices
+    ADD CONSTRAINT seat_type_prices_schedule_id_fkey FOREIGN KEY (schedule_id) REFERENCES public.schedules(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX seat_type_prices_cinema_key ON public.seat_type_prices (cinema_id, seat_type) WHERE cinema_id IS NOT NULL;
+
+CREATE UNIQUE INDEX seat_type_prices_schedule_key ON public.seat_type_prices (schedule_id, seat_type) WHERE schedule_id IS NOT NULL;
+
+-- Price paid for every seat, so order totals stay itemised even when the
+-- pricing changes later.
+ALTER TABLE public.order_details
+    ADD COLUMN price numeric;
@@ -0,0 +1,3 @@
+DROP TABLE voucher_usages;
+
+DROP TABLE vouchers
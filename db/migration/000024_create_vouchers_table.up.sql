@@ -0,0 +1,80 @@
+CREATE TABLE public.vouchers (
+    id integer NOT NULL,
+    code character varying NOT NULL,
+    description character varying DEFAULT ''::character varying,
+    discount_type character varying NOT NULL,
+    discount_value numeric NOT NULL,
+    max_discount numeric,
+    min_purchase numeric DEFAULT 0 NOT NULL,
+    starts_at timestamp without time zone NOT NULL,
+    ends_at timestamp without time zone NOT NULL,
+    usage_limit integer,
+    per_user_limit integer,
+    movie_id integer,
+    cinema_id integer,
+    city_id integer,
+    is_active boolean DEFAULT true NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    CONSTRAINT vouchers_discount_type_check CHECK (discount_type IN ('percentage', 'fixed')),
+    CONSTRAINT vouchers_discount_value_check CHECK (discount_value > 0 AND (discount_type <> 'percentage' OR discount_value <= 100)),
+    CONSTRAINT vouchers_window_check CHECK (starts_at < ends_at)
+);
+
+ALTER TABLE public.vouchers ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.vouchers_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+ALTER TABLE ONLY public.vouchers
+    ADD CONSTRAINT vouchers_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX vouchers_code_key ON public.vouchers (UPPER(code));
+
+ALTER TABLE ONLY public.vouchers
+    ADD CONSTRAINT vouchers_movie_id_fkey FOREIGN KEY (movie_id) REFERENCES public.movies(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.vouchers
+    ADD CONSTRAINT vouchers_cinema_id_fkey FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.vouchers
+    ADD CONSTRAINT vouchers_city_id_fkey FOREIGN KEY (city_id) REFERENCES public.cities(id) ON DELETE CASCADE;
+
+CREATE TABLE public.voucher_usages (
+    id integer NOT NULL,
+    voucher_id integer NOT NULL,
+    user_id integer NOT NULL,
+    order_id integer NOT NULL,
+    discount numeric NOT NULL,
+    created_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE public.voucher_usages ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.voucher_usages_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+ALTER TABLE ONLY public.voucher_usages
+    ADD CONSTRAINT voucher_usages_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.voucher_usages
+    ADD CONSTRAINT voucher_usages_order_id_key UNIQUE (order_id);
+
+ALTER TABLE ONLY public.voucher_usages
+    ADD CONSTRAINT voucher_usages_voucher_id_fkey FOREIGN KEY (voucher_id) REFERENCES public.vouchers(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY public.voucher_usages
+    ADD CONSTRAINT voucher_usages_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.voucher_usages
+    ADD CONSTRAINT voucher_usages_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.orders(id) ON DELETE CASCADE;
+
+CREATE INDEX voucher_usages_voucher_id_user_id_idx ON public.voucher_usages (voucher_id, user_id);
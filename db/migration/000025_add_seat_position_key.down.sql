@@ -0,0 +1 @@
+DROP INDEX IF EXISTS public.seats_cinema_position_key
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- A cinema can only have one seat at each row and number, so seat layouts can
+-- be saved with an upsert.
+CREATE UNIQUE INDEX seats_cinema_position_key ON public.seats (cinema_id, row_letter, seat_number);
@@ -0,0 +1,11 @@
+ALTER TABLE public.schedules
+    DROP COLUMN IF EXISTS auditorium_id;
+
+DROP INDEX IF EXISTS public.seats_auditorium_position_key;
+
+ALTER TABLE public.seats
+    DROP COLUMN IF EXISTS auditorium_id;
+
+CREATE UNIQUE INDEX seats_cinema_position_key ON public.seats (cinema_id, row_letter, seat_number);
+
+DROP TABLE auditoriums
\ No newline at end of file
@@ -0,0 +1,62 @@
+CREATE TABLE public.auditoriums (
+    id integer NOT NULL,
+    cinema_id integer NOT NULL,
+    name character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE public.auditoriums ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.auditoriums_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+ALTER TABLE ONLY public.auditoriums
+    ADD CONSTRAINT auditoriums_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.auditoriums
+    ADD CONSTRAINT auditoriums_cinema_id_name_key UNIQUE (cinema_id, name);
+
+-- Seats and schedules reference the auditorium together with its cinema, so
+-- their cinema_id always matches the auditorium's.
+ALTER TABLE ONLY public.auditoriums
+    ADD CONSTRAINT auditoriums_id_cinema_id_key UNIQUE (id, cinema_id);
+
+ALTER TABLE ONLY public.auditoriums
+    ADD CONSTRAINT auditoriums_cinema_id_fkey FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE CASCADE;
+
+-- Every existing cinema gets one auditorium holding its current seats and
+-- schedules.
+INSERT INTO public.auditoriums (cinema_id, name)
+SELECT id, 'Studio 1' FROM public.cinemas ORDER BY id;
+
+ALTER TABLE public.seats ADD COLUMN auditorium_id integer;
+
+UPDATE public.seats se
+SET auditorium_id = a.id
+FROM public.auditoriums a
+WHERE a.cinema_id = se.cinema_id;
+
+ALTER TABLE public.seats ALTER COLUMN auditorium_id SET NOT NULL;
+
+ALTER TABLE ONLY public.seats
+    ADD CONSTRAINT seats_auditorium_id_fkey FOREIGN KEY (auditorium_id, cinema_id) REFERENCES public.auditoriums(id, cinema_id) ON DELETE CASCADE;
+
+DROP INDEX public.seats_cinema_position_key;
+
+CREATE UNIQUE INDEX seats_auditorium_position_key ON public.seats (auditorium_id, row_letter, seat_number);
+
+ALTER TABLE public.schedules ADD COLUMN auditorium_id integer;
+
+UPDATE public.schedules s
+SET auditorium_id = a.id
+FROM public.auditoriums a
+WHERE a.cinema_id = s.cinema_id;
+
+ALTER TABLE public.schedules ALTER COLUMN auditorium_id SET NOT NULL;
+
+ALTER TABLE ONLY public.schedules
+    ADD CONSTRAINT schedules_auditorium_id_fkey FOREIGN KEY (auditorium_id, cinema_id) REFERENCES public.auditoriums(id, cinema_id) ON DELETE CASCADE;
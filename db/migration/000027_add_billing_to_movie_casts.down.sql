@@ -0,0 +1,3 @@
+ALTER TABLE public.movie_casts
+    DROP COLUMN IF EXISTS billing_order,
+    DROP COLUMN IF EXISTS character_name;
\ No newline at end of file
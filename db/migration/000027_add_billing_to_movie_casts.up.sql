@@ -0,0 +1,3 @@
+ALTER TABLE public.movie_casts
+    ADD COLUMN character_name character varying,
+    ADD COLUMN billing_order integer;
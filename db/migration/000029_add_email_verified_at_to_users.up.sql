@@ -0,0 +1,5 @@
+ALTER TABLE public.users
+    ADD COLUMN email_verified_at timestamp without time zone;
+
+-- Accounts created before email verification existed stay usable.
+UPDATE public.users SET email_verified_at = COALESCE(created_at, NOW());
@@ -0,0 +1,39 @@
+CREATE TABLE public.user_sessions (
+    id integer NOT NULL,
+    user_id integer NOT NULL,
+    family_id character varying NOT NULL,
+    user_agent character varying DEFAULT ''::character varying NOT NULL,
+    ip_address character varying DEFAULT ''::character varying NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    last_seen_at timestamp without time zone DEFAULT now() NOT NULL,
+    revoked_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE public.user_sessions ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.user_sessions_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+ALTER TABLE ONLY public.user_sessions
+    ADD CONSTRAINT user_sessions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.user_sessions
+    ADD CONSTRAINT user_sessions_family_id_key UNIQUE (family_id);
+
+CREATE INDEX user_sessions_user_id_idx ON public.user_sessions (user_id);
+
+ALTER TABLE ONLY public.user_sessions
+    ADD CONSTRAINT user_sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+-- Every refresh token family still in use becomes a session, so nobody is
+-- logged out by the upgrade.
+INSERT INTO public.user_sessions (user_id, family_id, expires_at, last_seen_at, created_at)
+SELECT user_id, family_id, MAX(expires_at), MAX(created_at), MIN(created_at)
+FROM public.refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY user_id, family_id;
@@ -1,11 +1,11 @@
-INSERT INTO "users" ("email", "password", "first_name", "last_name", "phone_number", "profile_image", "loyalty_points", "role") VALUES
-('john.doe@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567890', 'John', 'Doe', '+6281234567890', 'https://i.pravatar.cc/150?img=1', 150, 'user'),
-('jane.smith@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567891', 'Jane', 'Smith', '+6281234567891', 'https://i.pravatar.cc/150?img=2', 200, 'user'),
-('admin@tickitz.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567892', 'Admin', 'Tickitz', '+6281234567892', 'https://i.pravatar.cc/150?img=3', 0, 'admin'),
-('michael.brown@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567893', 'Michael', 'Brown', '+6281234567893', 'https://i.pravatar.cc/150?img=4', 300, 'user'),
-('sarah.wilson@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567894', 'Sarah', 'Wilson', '+6281234567894', 'https://i.pravatar.cc/150?img=5', 450, 'user'),
-('david.lee@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567895', 'David', 'Lee', '+6281234567895', 'https://i.pravatar.cc/150?img=6', 100, 'user'),
-('emily.davis@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567896', 'Emily', 'Davis', '+6281234567896', 'https://i.pravatar.cc/150?img=7', 250, 'user'),
-('robert.taylor@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567897', 'Robert', 'Taylor', '+6281234567897', 'https://i.pravatar.cc/150?img=8', 180, 'user'),
-('lisa.anderson@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567898', 'Lisa', 'Anderson', '+6281234567898', 'https://i.pravatar.cc/150?img=9', 320, 'user'),
-('james.martin@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567899', 'James', 'Martin', '+6281234567899', 'https://i.pravatar.cc/150?img=10', 500, 'user');
+INSERT INTO "users" ("email", "password", "first_name", "last_name", "phone_number", "profile_image", "loyalty_points", "role", "email_verified_at") VALUES
+('john.doe@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567890', 'John', 'Doe', '+6281234567890', 'https://i.pravatar.cc/150?img=1', 150, 'user', NOW()),
+('jane.smith@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567891', 'Jane', 'Smith', '+6281234567891', 'https://i.pravatar.cc/150?img=2', 200, 'user', NOW()),
+('admin@tickitz.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567892', 'Admin', 'Tickitz', '+6281234567892', 'https://i.pravatar.cc/150?img=3', 0, 'admin', NOW()),
+('michael.brown@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567893', 'Michael', 'Brown', '+6281234567893', 'https://i.pravatar.cc/150?img=4', 300, 'user', NOW()),
+('sarah.wilson@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567894', 'Sarah', 'Wilson', '+6281234567894', 'https://i.pravatar.cc/150?img=5', 450, 'user', NOW()),
+('david.lee@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567895', 'David', 'Lee', '+6281234567895', 'https://i.pravatar.cc/150?img=6', 100, 'user', NOW()),
+('emily.davis@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567896', 'Emily', 'Davis', '+6281234567896', 'https://i.pravatar.cc/150?img=7', 250, 'user', NOW()),
+('robert.taylor@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567897', 'Robert', 'Taylor', '+6281234567897', 'https://i.pravatar.cc/150?img=8', 180, 'user', NOW()),
+('lisa.anderson@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567898', 'Lisa', 'Anderson', '+6281234567898', 'https://i.pravatar.cc/150?img=9', 320, 'user', NOW()),
+('james.martin@email.com', '$2a$10$abcdefghijklmnopqrstuvwxyz1234567899', 'James', 'Martin', '+6281234567899', 'https://i.pravatar.cc/150?img=10', 500, 'user', NOW());
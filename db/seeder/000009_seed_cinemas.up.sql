@@ -9,3 +9,6 @@ INSERT INTO "cinemas" ("name", "logo_url", "location", "city_id") VALUES
 ('Cinepolis Ciputra Semarang', 'https://www.cinepolis.co.id/images/logo.png', 'Jl. Simpang Lima, Semarang', 6),
 ('XXI Panakkukang', 'https://www.21cineplex.com/images/logo.png', 'Jl. Boulevard, Makassar', 7),
 ('CGV Beachwalk', 'https://www.cgv.id/images/logo.png', 'Jl. Pantai Kuta, Denpasar', 8);
+
+INSERT INTO "auditoriums" ("cinema_id", "name")
+SELECT id, 'Studio 1' FROM cinemas ORDER BY id;
\ No newline at end of file
@@ -1,4 +1,6 @@
-INSERT INTO "seats" ("cinema_id", "row_letter", "seat_number", "seat_type") VALUES
+INSERT INTO "seats" ("cinema_id", "auditorium_id", "row_letter", "seat_number", "seat_type")
+SELECT v.cinema_id, a.id, v.row_letter, v.seat_number, v.seat_type
+FROM (VALUES
 (1, 'A', 1, 'regular'), (1, 'A', 2, 'regular'), (1, 'A', 3, 'regular'), (1, 'A', 4, 'regular'), (1, 'A', 5, 'regular'),
 (1, 'A', 6, 'regular'), (1, 'A', 7, 'regular'), (1, 'A', 8, 'regular'), (1, 'A', 9, 'regular'), (1, 'A', 10, 'regular'),
 (2, 'A', 1, 'regular'), (2, 'A', 2, 'regular'), (2, 'A', 3, 'regular'), (2, 'A', 4, 'regular'), (2, 'A', 5, 'regular'),
@@ -10,4 +12,6 @@ INSERT INTO "seats" ("cinema_id", "row_letter", "seat_number", "seat_type") VALU
 (5, 'A', 1, 'regular'), (5, 'A', 2, 'regular'), (5, 'A', 3, 'regular'), (5, 'A', 4, 'regular'), (5, 'A', 5, 'regular'),
 (5, 'A', 6, 'regular'), (5, 'A', 7, 'regular'), (5, 'A', 8, 'regular'), (5, 'A', 9, 'regular'), (5, 'A', 10, 'regular'),
 (6, 'A', 1, 'regular'), (6, 'A', 2, 'regular'), (6, 'A', 3, 'regular'), (6, 'A', 4, 'regular'), (6, 'A', 5, 'regular'),
-(6, 'B', 1, 'premium'), (6, 'B', 2, 'premium'), (6, 'B', 3, 'premium'), (6, 'B', 4, 'premium'), (6, 'B', 5, 'premium'),
+(6, 'B', 1, 'premium'), (6, 'B', 2, 'premium'), (6, 'B', 3, 'premium'), (6, 'B', 4, 'premium'), (6, 'B', 5, 'premium')
+) AS v (cinema_id, row_letter, seat_number, seat_type)
+INNER JOIN auditoriums a ON a.cinema_id = v.cinema_id AND a.name = 'Studio 1';
\ No newline at end of file
@@ -1,4 +1,6 @@
-INSERT INTO "schedules" ("movie_id", "cinema_id", "show_date", "show_time", "price") VALUES
+INSERT INTO "schedules" ("movie_id", "cinema_id", "auditorium_id", "show_date", "show_time", "price")
+SELECT v.movie_id, v.cinema_id, a.id, v.show_date::date, v.show_time::time, v.price
+FROM (VALUES
 (1, 1, '2026-01-15', '14:00:00', 50000),
 (2, 2, '2026-01-20', '19:30:00', 55000),
 (3, 3, '2026-01-22', '13:00:00', 45000),
@@ -8,4 +10,6 @@ INSERT INTO "schedules" ("movie_id", "cinema_id", "show_date", "show_time", "pri
 (7, 7, '2026-07-15', '18:00:00', 75000),
 (8, 8, '2026-09-01', '14:30:00', 60000),
 (9, 9, '2026-10-20', '19:00:00', 55000),
-(10, 10, '2026-12-28', '17:00:00', 80000);
\ No newline at end of file
+(10, 10, '2026-12-28', '17:00:00', 80000)
+) AS v (movie_id, cinema_id, show_date, show_time, price)
+INNER JOIN auditoriums a ON a.cinema_id = v.cinema_id AND a.name = 'Studio 1';
\ No newline at end of file
@@ -3,8 +3,10 @@ module github.com/Albaihaqi354/Tickitz-BE
 go 1.25.3
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/lpernett/godotenv v0.0.0-20230527005122-0de1d4c5ef5e
 	github.com/redis/go-redis/v9 v9.17.2
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/swaggo/swag v1.16.6
 )
 
@@ -9,17 +9,21 @@ import (
 )
 
 type JWTClaims struct {
-	Id    int    `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"Role"`
+	Id            int    `json:"id"`
+	SessionId     int    `json:"sid"`
+	Email         string `json:"email"`
+	Role          string `json:"Role"`
+	EmailVerified bool   `json:"email_verified"`
 	jwt.RegisteredClaims
 }
 
-func NewJWTClaim(id int, email, role string) *JWTClaims {
+func NewJWTClaim(id, sessionId int, email, role string, emailVerified bool) *JWTClaims {
 	return &JWTClaims{
-		Id:    id,
-		Email: email,
-		Role:  role,
+		Id:            id,
+		SessionId:     sessionId,
+		Email:         email,
+		Role:          role,
+		EmailVerified: emailVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
 			Issuer:    os.Getenv("JWT_ISSUER"),
@@ -0,0 +1,193 @@
+package pkg
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends plain text mail to users.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// NewMailer returns the mailer for MAIL_DRIVER. There is no default: "log"
+// does not send anything and is only meant for local development, so it has
+// to be chosen explicitly.
+func NewMailer(driver string) (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@tickitz.local"
+	}
+
+	switch driver {
+	case "":
+		return nil, errors.New("no mail driver configured, set MAIL_DRIVER")
+	case "log":
+		log.Println("MAIL_DRIVER=log: mail is not sent, use it for development only")
+		return NewLogMailer(os.Getenv("MAIL_DIR"), from), nil
+	case "smtp":
+		return NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USER"),
+			os.Getenv("SMTP_PASS"),
+			from,
+		), nil
+	}
+	return nil, fmt.Errorf("unknown mail driver %q", driver)
+}
+
+func formatMail(from string, mail Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// smtpTimeout bounds a whole SMTP conversation when ctx has no earlier
+// deadline, so a stuck server can not hold the request forever.
+func smtpTimeout() time.Duration {
+	return GetEnvDuration("SMTP_TIMEOUT", 10*time.Second)
+}
+
+// Send delivers the mail like smtp.SendMail, but gives up as soon as ctx is
+// done or the SMTP_TIMEOUT passes.
+func (m SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout())
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Unblock a pending read or write when ctx is cancelled before the deadline.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(mail.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(formatMail(m.from, mail)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// tokenParam matches the token query parameter of the links in a mail.
+var tokenParam = regexp.MustCompile(`([?&]token=)[^&\s]+`)
+
+// redactTokens hides the tokens in mail links, so they do not end up in logs
+// where anyone reading them could use them.
+func redactTokens(message []byte) []byte {
+	return tokenParam.ReplaceAll(message, []byte("${1}REDACTED"))
+}
+
+// LogMailer is a development mailer. It logs every mail with the tokens in
+// its links redacted and, when dir is set, also saves the full mail there as
+// an .eml file that can be opened with a mail client.
+type LogMailer struct {
+	dir  string
+	from string
+}
+
+func NewLogMailer(dir, from string) *LogMailer {
+	return &LogMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (m LogMailer) Send(ctx context.Context, mail Mail) error {
+	message := formatMail(m.from, mail)
+	if m.dir == "" {
+		log.Printf("Mail to %s:\n%s", mail.To, redactTokens(message))
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	recipient := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, mail.To)
+	name := filepath.Join(m.dir, fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), recipient))
+	if err := os.WriteFile(name, message, 0o644); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s saved to %s", mail.To, name)
+	return nil
+}
@@ -0,0 +1,34 @@
+package pkg
+
+import "testing"
+
+func TestRedactTokens(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"open http://localhost/auth/verify?token=VRF1.7.123.abc.def\r\n", "open http://localhost/auth/verify?token=REDACTED\r\n"},
+		{"http://localhost/reset?lang=id&token=abc123&next=/", "http://localhost/reset?lang=id&token=REDACTED&next=/"},
+		{"no link here", "no link here"},
+	}
+
+	for _, tt := range tests {
+		if got := string(redactTokens([]byte(tt.in))); got != tt.want {
+			t.Errorf("redactTokens(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewMailer(t *testing.T) {
+	if _, err := NewMailer(""); err == nil {
+		t.Error(`NewMailer("") succeeded, want an error`)
+	}
+	if _, err := NewMailer("unknown"); err == nil {
+		t.Error(`NewMailer("unknown") succeeded, want an error`)
+	}
+	for _, driver := range []string{"log", "smtp"} {
+		if _, err := NewMailer(driver); err != nil {
+			t.Errorf("NewMailer(%q) error = %v", driver, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SimulatedProvider never talks to a real gateway. Payments are completed
+// by posting a signed event to the webhook, which Simulate does for local
+// development. It is only used with PAYMENT_PROVIDER=simulated and must never
+// be enabled in production.
+type SimulatedProvider struct {
+	secret string
+}
+
+func NewSimulatedProvider(secret string) *SimulatedProvider {
+	return &SimulatedProvider{
+		secret: secret,
+	}
+}
+
+func (s *SimulatedProvider) Name() string {
+	return "simulated"
+}
+
+func (s *SimulatedProvider) CreatePayment(ctx context.Context, req PaymentRequest) (PaymentSession, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return PaymentSession{}, err
+	}
+	reference := fmt.Sprintf("SIM-%d-%s", req.OrderId, hex.EncodeToString(b))
+
+	return PaymentSession{
+		Reference:  reference,
+		PaymentUrl: "/payments/simulate/" + reference,
+	}, nil
+}
+
+func (s *SimulatedProvider) ParseWebhook(payload []byte, signature string) (PaymentEvent, error) {
+	if s.secret == "" {
+		return PaymentEvent{}, errors.New("no secret found")
+	}
+	if !VerifyPayloadSignature(s.secret, payload, signature) {
+		return PaymentEvent{}, ErrInvalidSignature
+	}
+
+	var event PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return PaymentEvent{}, err
+	}
+	return event, nil
+}
+
+func (s *SimulatedProvider) Refund(ctx context.Context, reference string, amount int) error {
+	return nil
+}
+
+// Simulate builds the signed webhook payload the gateway would send for the
+// given payment.
+func (s *SimulatedProvider) Simulate(event PaymentEvent) ([]byte, string, error) {
+	if s.secret == "" {
+		return nil, "", errors.New("no secret found")
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, SignPayload(s.secret, payload), nil
+}
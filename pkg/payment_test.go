@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyPayloadSignature(t *testing.T) {
+	payload := []byte(`{"reference":"SIM-1-abc","status":"paid","amount":50000}`)
+	signature := SignPayload("secret", payload)
+	tampered := "0"
+	if signature[0] == '0' {
+		tampered = "1"
+	}
+	tampered += signature[1:]
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{"valid", "secret", payload, signature, true},
+		{"wrong secret", "other", payload, signature, false},
+		{"tampered payload", "secret", []byte(`{"reference":"SIM-1-abc","status":"paid","amount":1}`), signature, false},
+		{"tampered signature", "secret", payload, tampered, false},
+		{"not hex", "secret", payload, "not-a-signature", false},
+		{"empty signature", "secret", payload, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPayloadSignature(tt.secret, tt.payload, tt.signature); got != tt.want {
+				t.Errorf("VerifyPayloadSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulatedProviderWebhookRoundTrip(t *testing.T) {
+	provider := NewSimulatedProvider("secret")
+	event := PaymentEvent{Reference: "SIM-1-abc", Status: PaymentStatusPaid, Amount: 50000}
+
+	payload, signature, err := provider.Simulate(event)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	got, err := provider.ParseWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if got != event {
+		t.Errorf("ParseWebhook() = %+v, want %+v", got, event)
+	}
+
+	if _, err := NewSimulatedProvider("other").ParseWebhook(payload, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseWebhook() with another secret error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := NewSimulatedProvider("").ParseWebhook(payload, signature); err == nil {
+		t.Error("ParseWebhook() without a secret succeeded")
+	}
+}
+
+func TestNewPaymentProvider(t *testing.T) {
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "")
+	if _, err := NewPaymentProvider("simulated"); err == nil {
+		t.Error(`NewPaymentProvider("simulated") without PAYMENT_WEBHOOK_SECRET succeeded, want an error`)
+	}
+
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "secret")
+	if _, err := NewPaymentProvider(""); err == nil {
+		t.Error(`NewPaymentProvider("") succeeded, want an error`)
+	}
+	if _, err := NewPaymentProvider("unknown"); err == nil {
+		t.Error(`NewPaymentProvider("unknown") succeeded, want an error`)
+	}
+	provider, err := NewPaymentProvider("simulated")
+	if err != nil {
+		t.Fatalf(`NewPaymentProvider("simulated") error = %v`, err)
+	}
+	if _, ok := provider.(*SimulatedProvider); !ok {
+		t.Errorf(`NewPaymentProvider("simulated") = %T, want *SimulatedProvider`, provider)
+	}
+}
@@ -0,0 +1,10 @@
+package pkg
+
+import (
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// GenerateQRCode renders content as a square PNG QR code of size pixels.
+func GenerateQRCode(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const ticketPayloadPrefix = "TKZ1"
+
+var (
+	ErrInvalidTicket = errors.New("invalid ticket payload")
+	// ErrNoTicketSecret is returned instead of signing or accepting tickets
+	// with an empty key, which anyone could forge.
+	ErrNoTicketSecret = errors.New("no ticket signing secret found")
+)
+
+// TicketPayload is what the QR code on an e-ticket carries. Gates can check
+// it offline with the signing secret, without calling the API.
+type TicketPayload struct {
+	OrderId     int
+	BookingCode string
+}
+
+func ticketSecret() string {
+	return os.Getenv("TICKET_SIGNING_SECRET")
+}
+
+// SignTicket encodes the ticket as TKZ1.<order id>.<booking code>.<hmac>.
+func SignTicket(orderId int, bookingCode string) (string, error) {
+	secret := ticketSecret()
+	if secret == "" {
+		return "", ErrNoTicketSecret
+	}
+	body := fmt.Sprintf("%s.%d.%s", ticketPayloadPrefix, orderId, bookingCode)
+	return body + "." + SignPayload(secret, []byte(body)), nil
+}
+
+func ParseTicket(payload string) (TicketPayload, error) {
+	secret := ticketSecret()
+	if secret == "" {
+		return TicketPayload{}, ErrNoTicketSecret
+	}
+
+	parts := strings.Split(strings.TrimSpace(payload), ".")
+	if len(parts) != 4 || parts[0] != ticketPayloadPrefix {
+		return TicketPayload{}, ErrInvalidTicket
+	}
+
+	body := strings.Join(parts[:3], ".")
+	if !VerifyPayloadSignature(secret, []byte(body), parts[3]) {
+		return TicketPayload{}, ErrInvalidTicket
+	}
+
+	orderId, err := strconv.Atoi(parts[1])
+	if err != nil || parts[2] == "" {
+		return TicketPayload{}, ErrInvalidTicket
+	}
+	return TicketPayload{
+		OrderId:     orderId,
+		BookingCode: parts[2],
+	}, nil
+}
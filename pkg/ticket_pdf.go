@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// TicketDocument holds everything printed on a PDF e-ticket. Poster and
+// QRCode are raw image bytes; the poster is skipped when it is empty or not
+// a JPEG/PNG image.
+type TicketDocument struct {
+	Title          string
+	Poster         []byte
+	CinemaName     string
+	Auditorium     string
+	CinemaLocation string
+	City           string
+	ShowDate       time.Time
+	ShowTime       time.Time
+	Seats          []string
+	BookingCode    string
+	TotalPrice     int
+	QRCode         []byte
+}
+
+func imageType(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return "JPG"
+	case "image/png":
+		return "PNG"
+	}
+	return ""
+}
+
+func RenderTicketPDF(doc TicketDocument) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A5", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Tickitz e-ticket "+doc.BookingCode, true)
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(false, 12)
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 24
+
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.SetTextColor(95, 46, 234)
+	pdf.CellFormat(contentWidth, 10, "Tickitz", "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(2)
+
+	textX := 12.0
+	top := pdf.GetY()
+	if kind := imageType(doc.Poster); kind != "" {
+		pdf.RegisterImageOptionsReader("poster", fpdf.ImageOptions{ImageType: kind}, bytes.NewReader(doc.Poster))
+		pdf.ImageOptions("poster", 12, top, 36, 54, false, fpdf.ImageOptions{ImageType: kind}, 0, "")
+		textX = 52
+	}
+
+	pdf.SetXY(textX, top)
+	pdf.SetFont("Helvetica", "B", 15)
+	pdf.MultiCell(pageWidth-12-textX, 7, tr(doc.Title), "", "L", false)
+
+	details := [][2]string{
+		{"Cinema", doc.CinemaName},
+		{"Studio", doc.Auditorium},
+		{"Location", fmt.Sprintf("%s, %s", doc.CinemaLocation, doc.City)},
+		{"Date", doc.ShowDate.Format("Monday, 02 January 2006")},
+		{"Time", doc.ShowTime.Format("15:04")},
+		{"Seats", strings.Join(doc.Seats, ", ")},
+		{"Total", fmt.Sprintf("Rp %d", doc.TotalPrice)},
+	}
+	for _, d := range details {
+		pdf.SetX(textX)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(pageWidth-12-textX, 5, d[0], "", 1, "L", false, 0, "")
+		pdf.SetX(textX)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.MultiCell(pageWidth-12-textX, 5.5, tr(d[1]), "", "L", false)
+	}
+
+	y := max(pdf.GetY(), top+54) + 8
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.SetDashPattern([]float64{2, 1}, 0)
+	pdf.Line(12, y, pageWidth-12, y)
+	pdf.SetDashPattern([]float64{}, 0)
+
+	qrSize := 60.0
+	qrX := (pageWidth - qrSize) / 2
+	if imageType(doc.QRCode) == "PNG" {
+		pdf.RegisterImageOptionsReader("qrcode", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(doc.QRCode))
+		pdf.ImageOptions("qrcode", qrX, y+6, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	}
+
+	pdf.SetXY(12, y+6+qrSize+2)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetTextColor(120, 120, 120)
+	pdf.CellFormat(contentWidth, 5, "Booking code", "", 1, "C", false, 0, "")
+	pdf.SetFont("Courier", "B", 12)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(contentWidth, 6, doc.BookingCode, "", 1, "C", false, 0, "")
+	pdf.Ln(3)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetTextColor(120, 120, 120)
+	pdf.MultiCell(contentWidth, 4, "Show this QR code at the entrance. The ticket is valid for one admission only.", "", "C", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTicketRoundTrip(t *testing.T) {
+	t.Setenv("TICKET_SIGNING_SECRET", "secret")
+
+	payload, err := SignTicket(42, "TKZ-ABC123")
+	if err != nil {
+		t.Fatalf("SignTicket() error = %v", err)
+	}
+	got, err := ParseTicket(" " + payload + "\n")
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	want := TicketPayload{OrderId: 42, BookingCode: "TKZ-ABC123"}
+	if got != want {
+		t.Errorf("ParseTicket() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTicketRejectsInvalidPayloads(t *testing.T) {
+	t.Setenv("TICKET_SIGNING_SECRET", "secret")
+
+	payload, err := SignTicket(42, "TKZ-ABC123")
+	if err != nil {
+		t.Fatalf("SignTicket() error = %v", err)
+	}
+	parts := strings.Split(payload, ".")
+
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"empty", ""},
+		{"booking code only", "TKZ-ABC123"},
+		{"wrong prefix", strings.Join(append([]string{"TKZ2"}, parts[1:]...), ".")},
+		{"other order", strings.Join([]string{parts[0], "43", parts[2], parts[3]}, ".")},
+		{"other booking code", strings.Join([]string{parts[0], parts[1], "TKZ-XYZ999", parts[3]}, ".")},
+		{"bad signature", strings.Join([]string{parts[0], parts[1], parts[2], "00"}, ".")},
+		{"missing signature", strings.Join(parts[:3], ".")},
+		{"extra part", payload + ".x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTicket(tt.payload); !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("ParseTicket() error = %v, want %v", err, ErrInvalidTicket)
+			}
+		})
+	}
+}
+
+func TestTicketSignedWithAnotherSecret(t *testing.T) {
+	t.Setenv("TICKET_SIGNING_SECRET", "old")
+	payload, err := SignTicket(42, "TKZ-ABC123")
+	if err != nil {
+		t.Fatalf("SignTicket() error = %v", err)
+	}
+
+	t.Setenv("TICKET_SIGNING_SECRET", "new")
+	if _, err := ParseTicket(payload); !errors.Is(err, ErrInvalidTicket) {
+		t.Errorf("ParseTicket() error = %v, want %v", err, ErrInvalidTicket)
+	}
+}
+
+func TestTicketWithoutSecret(t *testing.T) {
+	t.Setenv("TICKET_SIGNING_SECRET", "secret")
+	payload, err := SignTicket(42, "TKZ-ABC123")
+	if err != nil {
+		t.Fatalf("SignTicket() error = %v", err)
+	}
+
+	t.Setenv("TICKET_SIGNING_SECRET", "")
+	if _, err := SignTicket(42, "TKZ-ABC123"); !errors.Is(err, ErrNoTicketSecret) {
+		t.Errorf("SignTicket() error = %v, want %v", err, ErrNoTicketSecret)
+	}
+	if _, err := ParseTicket(payload); !errors.Is(err, ErrNoTicketSecret) {
+		t.Errorf("ParseTicket() error = %v, want %v", err, ErrNoTicketSecret)
+	}
+}
@@ -0,0 +1,33 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// NewOpaqueToken returns a random URL-safe token carrying size bytes of
+// entropy. Opaque tokens mean nothing by themselves; the server looks them up.
+func NewOpaqueToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the SHA-256 of an opaque token. Only the hash is stored,
+// so a leaked table does not give away usable tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RefreshTokenTTL is how long a refresh token can be exchanged for a new
+// access token. Every exchange issues a new refresh token with a fresh TTL,
+// so a session lasts until it has been idle for this long.
+func RefreshTokenTTL() time.Duration {
+	return GetEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailVerificationRoundTrip(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_SECRET", "secret")
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	token, err := SignEmailVerification(7, "user@example.com", expiresAt)
+	if err != nil {
+		t.Fatalf("SignEmailVerification() error = %v", err)
+	}
+	got, err := ParseEmailVerification(token)
+	if err != nil {
+		t.Fatalf("ParseEmailVerification() error = %v", err)
+	}
+	if got.UserId != 7 || got.Email != "user@example.com" || !got.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("ParseEmailVerification() = %+v, want user 7, user@example.com, %v", got, expiresAt)
+	}
+}
+
+func TestParseEmailVerificationRejectsInvalidTokens(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_SECRET", "secret")
+
+	token, err := SignEmailVerification(7, "user@example.com", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SignEmailVerification() error = %v", err)
+	}
+	other, err := SignEmailVerification(8, "other@example.com", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SignEmailVerification() error = %v", err)
+	}
+	parts := strings.Split(token, ".")
+	otherParts := strings.Split(other, ".")
+	replace := func(i int, value string) string {
+		changed := append([]string(nil), parts...)
+		changed[i] = value
+		return strings.Join(changed, ".")
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"wrong prefix", replace(0, "VRF2")},
+		{"other user", replace(1, "8")},
+		{"later expiry", replace(2, "9999999999")},
+		{"other email", replace(3, otherParts[3])},
+		{"signature of another token", replace(4, otherParts[4])},
+		{"missing signature", strings.Join(parts[:4], ".")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseEmailVerification(tt.token); !errors.Is(err, ErrInvalidVerification) {
+				t.Errorf("ParseEmailVerification() error = %v, want %v", err, ErrInvalidVerification)
+			}
+		})
+	}
+}
+
+func TestParseEmailVerificationExpired(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_SECRET", "secret")
+
+	token, err := SignEmailVerification(7, "user@example.com", time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatalf("SignEmailVerification() error = %v", err)
+	}
+	if _, err := ParseEmailVerification(token); !errors.Is(err, ErrExpiredVerification) {
+		t.Errorf("ParseEmailVerification() error = %v, want %v", err, ErrExpiredVerification)
+	}
+}
+
+func TestEmailVerificationWithoutSecret(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_SECRET", "secret")
+	token, err := SignEmailVerification(7, "user@example.com", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SignEmailVerification() error = %v", err)
+	}
+
+	t.Setenv("EMAIL_VERIFICATION_SECRET", "")
+	if _, err := SignEmailVerification(7, "user@example.com", time.Now().Add(time.Hour)); !errors.Is(err, ErrNoVerificationSecret) {
+		t.Errorf("SignEmailVerification() error = %v, want %v", err, ErrNoVerificationSecret)
+	}
+	if _, err := ParseEmailVerification(token); !errors.Is(err, ErrNoVerificationSecret) {
+		t.Errorf("ParseEmailVerification() error = %v, want %v", err, ErrNoVerificationSecret)
+	}
+}